@@ -0,0 +1,199 @@
+// Command spy-cat-admin runs maintenance tasks against the spy cat database.
+//
+// Usage:
+//
+//	spy-cat-admin <command>
+//
+// The configuration is read from CONFIG_PATH, just like the server.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/illiakornyk/spy-cat/internal/config"
+	"github.com/illiakornyk/spy-cat/internal/countries"
+	"github.com/illiakornyk/spy-cat/internal/logger"
+	"github.com/illiakornyk/spy-cat/internal/service"
+	"github.com/illiakornyk/spy-cat/internal/storage/initializer"
+	"github.com/illiakornyk/spy-cat/internal/storage/sqlite"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(cfg *config.Config, storage *sqlite.Storage, logger *slog.Logger, args []string) int
+}
+
+var commands = []command{
+	{
+		name:  "integrity-check",
+		usage: "report database corruption and rows that violate foreign keys",
+		run:   integrityCheck,
+	},
+	{
+		name:  "rotate-keys",
+		usage: "re-encrypt all target notes with the active encryption key",
+		run:   rotateKeys,
+	},
+	{
+		name:  "prune-blobs",
+		usage: "remove attachment content no attachment refers to any more",
+		run:   pruneBlobs,
+	},
+	{
+		name:  "normalize-countries",
+		usage: "rewrite target countries as ISO 3166-1 alpha-2 codes [-dry-run]",
+		run:   normalizeCountries,
+	},
+}
+
+// pruneGrace keeps blobs stored recently, whose upload may not have been
+// recorded yet.
+const pruneGrace = time.Hour
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name != os.Args[1] {
+			continue
+		}
+
+		cfg := config.MustLoad()
+
+		logger := logger.SetupLogger(cfg.Env)
+		logger = logger.With(slog.String("env", cfg.Env), slog.String("command", c.name))
+
+		storage := initializer.InitializeStorage(cfg, logger)
+
+		os.Exit(c.run(cfg, storage, logger, os.Args[2:]))
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+	printUsage()
+	os.Exit(2)
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: spy-cat-admin <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-20s %s\n", c.name, c.usage)
+	}
+}
+
+func integrityCheck(_ *config.Config, storage *sqlite.Storage, logger *slog.Logger, _ []string) int {
+	violations, err := storage.CheckIntegrity(context.Background())
+	if err != nil {
+		logger.Error("integrity check failed", slog.Any("error", err))
+		return 1
+	}
+
+	for _, v := range violations {
+		fmt.Println(v.Detail)
+	}
+
+	if len(violations) > 0 {
+		logger.Warn("integrity violations found", slog.Int("count", len(violations)))
+		return 1
+	}
+
+	logger.Info("no integrity violations found")
+	return 0
+}
+
+// rotateKeys re-encrypts every target's notes that are not sealed with the
+// active key. Run it after changing active_key, and before removing an old
+// key from the configuration.
+func rotateKeys(cfg *config.Config, storage *sqlite.Storage, logger *slog.Logger, _ []string) int {
+	count, err := storage.ReencryptNotes(context.Background())
+	if err != nil {
+		logger.Error("failed to re-encrypt notes", slog.Any("error", err))
+		return 1
+	}
+
+	logger.Info("notes re-encrypted", slog.Int("rows", count), slog.String("active_key", cfg.Encryption.ActiveKey))
+	return 0
+}
+
+// pruneBlobs removes the content of attachments that no longer exist, such
+// as those of deleted targets and missions.
+func pruneBlobs(cfg *config.Config, storage *sqlite.Storage, logger *slog.Logger, _ []string) int {
+	attachments := service.NewAttachmentService(storage, initializer.InitializeBlobStore(cfg, logger), service.AttachmentOptions{})
+
+	count, err := attachments.PruneBlobs(context.Background(), pruneGrace)
+	if err != nil {
+		logger.Error("failed to prune blobs", slog.Any("error", err))
+		return 1
+	}
+
+	logger.Info("blobs pruned", slog.Int("count", count))
+	return 0
+}
+
+// normalizeCountries rewrites the country of every target recorded before
+// countries were validated, such as "United Kingdom" or "england", as its
+// alpha-2 code. Values that name no known country are listed and left
+// alone; the command then exits with a non-zero status.
+func normalizeCountries(_ *config.Config, storage *sqlite.Storage, logger *slog.Logger, args []string) int {
+	flags := flag.NewFlagSet("normalize-countries", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report the changes without making them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	ctx := context.Background()
+	counts, err := storage.TargetCountries(ctx)
+	if err != nil {
+		logger.Error("failed to list countries", slog.Any("error", err))
+		return 1
+	}
+
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	var changed, unresolved int
+	for _, value := range values {
+		country, ok := countries.Lookup(value)
+		if !ok {
+			fmt.Printf("unresolved: %q (%d targets)\n", value, counts[value])
+			unresolved++
+			continue
+		}
+		if country.Alpha2 == value {
+			continue
+		}
+
+		fmt.Printf("%q -> %s (%d targets)\n", value, country.Alpha2, counts[value])
+		if *dryRun {
+			changed += counts[value]
+			continue
+		}
+
+		n, err := storage.RenameTargetCountry(ctx, value, country.Alpha2)
+		if err != nil {
+			logger.Error("failed to normalize country", slog.String("country", value), slog.Any("error", err))
+			return 1
+		}
+		changed += int(n)
+	}
+
+	logger.Info("countries normalized", slog.Int("targets", changed), slog.Int("unresolved", unresolved), slog.Bool("dry_run", *dryRun))
+	if unresolved > 0 {
+		return 1
+	}
+	return 0
+}
@@ -1,13 +1,20 @@
 package main
 
 import (
+	"context"
 	"log/slog"
-	"time"
+	"os"
 
+	"github.com/illiakornyk/spy-cat/internal/auth"
 	"github.com/illiakornyk/spy-cat/internal/breeds"
 	"github.com/illiakornyk/spy-cat/internal/config"
+	"github.com/illiakornyk/spy-cat/internal/events"
 	"github.com/illiakornyk/spy-cat/internal/http-server/router"
 	"github.com/illiakornyk/spy-cat/internal/logger"
+	"github.com/illiakornyk/spy-cat/internal/matching"
+	"github.com/illiakornyk/spy-cat/internal/rules"
+	"github.com/illiakornyk/spy-cat/internal/scheduler"
+	"github.com/illiakornyk/spy-cat/internal/service"
 	"github.com/illiakornyk/spy-cat/internal/storage/initializer"
 )
 
@@ -17,10 +24,57 @@ func main() {
 	logger := logger.SetupLogger(cfg.Env)
 	logger = logger.With(slog.String("env", cfg.Env))
 
-	storage := initializer.InitializeStorage(cfg.StoragePath, logger)
-	breeds.StartBreedCache(24 * time.Hour)
+	storage := initializer.InitializeStorage(cfg, logger)
+	blobs := initializer.InitializeBlobStore(cfg, logger)
 
-	r := router.SetupRouter(logger, storage)
+	breedProvider := breeds.NewHTTPProvider(breeds.HTTPProviderOptions{
+		URL:              cfg.Breeds.URL,
+		APIKey:           cfg.Breeds.APIKey,
+		Timeout:          cfg.Breeds.Timeout,
+		Retries:          cfg.Breeds.Retries,
+		Backoff:          cfg.Breeds.Backoff,
+		BreakerThreshold: cfg.Breeds.BreakerThreshold,
+		BreakerCooldown:  cfg.Breeds.BreakerCooldown,
+	})
+	breedCache := breeds.NewCache(breedProvider, breeds.CacheOptions{
+		SnapshotPath:    cfg.Breeds.SnapshotPath,
+		RefreshInterval: cfg.Breeds.RefreshInterval,
+		RetryInterval:   cfg.Breeds.RetryInterval,
+	}, logger)
+	breedCache.Start(context.Background())
 
-	router.StartServer(cfg.HTTPServer.Address, r, logger)
+	overdueScheduler := scheduler.NewOverdueScheduler(storage, events.NewLogPublisher(logger), cfg.Scheduler.OverdueCheckInterval, logger)
+	overdueScheduler.Start(context.Background())
+
+	rulesEngine, err := rules.New(cfg.Rules)
+	if err != nil {
+		logger.Error("Invalid rules configuration", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	services := &service.Services{
+		Breeds:   service.NewBreedService(breedCache),
+		Cats:     service.NewCatService(storage, breedCache),
+		Missions: service.NewMissionService(storage, rulesEngine),
+		Matching: service.NewMatchingService(storage, rulesEngine, matching.New(cfg.Matching), breedCache),
+		Targets:  service.NewTargetService(storage, rulesEngine),
+
+		Attachments: service.NewAttachmentService(storage, blobs, service.AttachmentOptions{
+			MaxSize:      cfg.Attachments.MaxSize,
+			AllowedTypes: cfg.Attachments.AllowedTypes,
+		}),
+		Audit:    service.NewAuditService(storage),
+		Search:   service.NewSearchService(storage),
+		Transfer: service.NewTransferService(storage, breedCache, rulesEngine),
+	}
+
+	authenticator, err := auth.NewAuthenticator(cfg.Auth)
+	if err != nil {
+		logger.Error("Invalid auth configuration", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	r := router.SetupRouter(logger, services, authenticator, breedCache, cfg.HTTPServer.Timeout)
+
+	router.StartServer(cfg.HTTPServer, r, logger)
 }
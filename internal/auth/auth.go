@@ -0,0 +1,95 @@
+// Package auth identifies the principal behind a request and carries it
+// through the request context.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/config"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Name      string
+	Clearance domain.Classification
+}
+
+// Anonymous is the principal of callers that present no key when no other
+// anonymous clearance is configured.
+var Anonymous = Principal{Name: "anonymous", Clearance: domain.ClassificationPublic}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying p.
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the principal carried by ctx, or Anonymous when there
+// is none.
+func FromContext(ctx context.Context) Principal {
+	if p, ok := ctx.Value(contextKey{}).(Principal); ok {
+		return p
+	}
+	return Anonymous
+}
+
+// Authenticator maps API keys to principals.
+type Authenticator struct {
+	principals []principalKey
+	anonymous  Principal
+}
+
+type principalKey struct {
+	key       []byte
+	principal Principal
+}
+
+// NewAuthenticator builds an Authenticator from cfg. It fails when a
+// clearance is unknown or a principal has no key.
+func NewAuthenticator(cfg config.Auth) (*Authenticator, error) {
+	const op = "auth.NewAuthenticator"
+
+	a := &Authenticator{anonymous: Anonymous}
+	if cfg.AnonymousClearance != "" {
+		clearance, err := domain.ParseClassification(cfg.AnonymousClearance)
+		if err != nil {
+			return nil, fmt.Errorf("%s: anonymous_clearance: %w", op, err)
+		}
+		a.anonymous.Clearance = clearance
+	}
+
+	for _, p := range cfg.Principals {
+		if p.APIKey == "" {
+			return nil, fmt.Errorf("%s: principal %q has no api_key", op, p.Name)
+		}
+		clearance, err := domain.ParseClassification(p.Clearance)
+		if err != nil {
+			return nil, fmt.Errorf("%s: principal %q: %w", op, p.Name, err)
+		}
+		a.principals = append(a.principals, principalKey{
+			key:       []byte(p.APIKey),
+			principal: Principal{Name: p.Name, Clearance: clearance},
+		})
+	}
+
+	return a, nil
+}
+
+// Authenticate returns the principal owning key. An empty key yields the
+// anonymous principal; an unknown key yields false.
+func (a *Authenticator) Authenticate(key string) (Principal, bool) {
+	if key == "" {
+		return a.anonymous, true
+	}
+
+	for _, p := range a.principals {
+		if subtle.ConstantTimeCompare(p.key, []byte(key)) == 1 {
+			return p.principal, true
+		}
+	}
+	return Principal{}, false
+}
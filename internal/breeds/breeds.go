@@ -1,33 +1,147 @@
 package breeds
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
 )
 
-type Breed struct {
-    ID   string `json:"id"`
-    Name string `json:"name"`
+const DefaultURL = "https://api.thecatapi.com/v1/breeds"
+
+type Breed = domain.Breed
+
+// BreedProvider is a source of the breed list.
+type BreedProvider interface {
+	FetchBreeds(ctx context.Context) ([]Breed, error)
+}
+
+// ErrCircuitOpen is returned by HTTPProvider while the upstream API is
+// considered down and requests are not being attempted.
+var ErrCircuitOpen = errors.New("breed provider circuit is open")
+
+// HTTPProviderOptions configures an HTTPProvider. Zero values fall back to
+// sensible defaults.
+type HTTPProviderOptions struct {
+	URL              string
+	APIKey           string
+	Timeout          time.Duration
+	Retries          int
+	Backoff          time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
 }
 
-func FetchBreeds() ([]Breed, error) {
-    url := "https://api.thecatapi.com/v1/breeds"
-    req, _ := http.NewRequest("GET", url, nil)
-
-    client := &http.Client{}
-    resp, err := client.Do(req)
-    if err != nil {
-        log.Printf("Error fetching breeds: %v", err)
-        return nil, err
-    }
-    defer resp.Body.Close()
-
-    var breeds []Breed
-    if err := json.NewDecoder(resp.Body).Decode(&breeds); err != nil {
-        log.Printf("Error decoding breed response: %v", err)
-        return nil, err
-    }
-
-    return breeds, nil
+// HTTPProvider fetches breeds from TheCatAPI or any endpoint serving the
+// same JSON shape.
+type HTTPProvider struct {
+	url     string
+	apiKey  string
+	client  *http.Client
+	retries int
+	backoff time.Duration
+	breaker *circuitBreaker
+}
+
+func NewHTTPProvider(opts HTTPProviderOptions) *HTTPProvider {
+	if opts.URL == "" {
+		opts.URL = DefaultURL
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = 500 * time.Millisecond
+	}
+
+	return &HTTPProvider{
+		url:     opts.URL,
+		apiKey:  opts.APIKey,
+		client:  &http.Client{Timeout: opts.Timeout},
+		retries: opts.Retries,
+		backoff: opts.Backoff,
+		breaker: newCircuitBreaker(opts.BreakerThreshold, opts.BreakerCooldown),
+	}
+}
+
+// FetchBreeds requests the breed list, retrying transient failures with
+// exponential backoff. Once the circuit breaker trips, calls fail fast with
+// ErrCircuitOpen until the cooldown has passed.
+func (p *HTTPProvider) FetchBreeds(ctx context.Context) ([]Breed, error) {
+	const op = "breeds.HTTPProvider.FetchBreeds"
+
+	if !p.breaker.allow() {
+		return nil, fmt.Errorf("%s: %w", op, ErrCircuitOpen)
+	}
+
+	backoff := p.backoff
+	var lastErr error
+	for attempt := 0; attempt <= p.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				p.breaker.failure()
+				return nil, fmt.Errorf("%s: %w", op, ctx.Err())
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		breeds, retry, err := p.fetch(ctx)
+		if err == nil {
+			p.breaker.success()
+			return breeds, nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+
+	p.breaker.failure()
+	return nil, fmt.Errorf("%s: %w", op, lastErr)
+}
+
+// CircuitState reports the breaker state: "closed", "open" or "half-open".
+func (p *HTTPProvider) CircuitState() string {
+	return p.breaker.state()
+}
+
+func (p *HTTPProvider) fetch(ctx context.Context) (breeds []Breed, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("x-api-key", p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Client errors other than rate limiting will not fix themselves.
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return nil, retry, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&breeds); err != nil {
+		return nil, false, fmt.Errorf("decoding breed response: %w", err)
+	}
+	if len(breeds) == 0 {
+		return nil, false, errors.New("empty breed list")
+	}
+
+	return breeds, false, nil
 }
@@ -1,51 +1,271 @@
 package breeds
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 )
 
-var (
-    breedCache []Breed
-    cacheMutex sync.RWMutex
+// Source identifies where the breeds currently held by a Cache came from.
+type Source string
+
+const (
+	SourceAPI      Source = "api"
+	SourceSnapshot Source = "snapshot"
+	SourceBundled  Source = "bundled"
 )
 
-func StartBreedCache( interval time.Duration) {
-    updateBreeds()
-    ticker := time.NewTicker(interval)
-    go func() {
-        for {
-            <-ticker.C
-            updateBreeds()
-        }
-    }()
+type CacheOptions struct {
+	// SnapshotPath is where the last successfully fetched list is persisted
+	// and loaded from at boot. Leave empty to disable snapshots.
+	SnapshotPath string
+	// RefreshInterval is the delay between refreshes after a success.
+	RefreshInterval time.Duration
+	// RetryInterval is the first delay after a failed refresh. It doubles
+	// with every consecutive failure, up to RefreshInterval.
+	RetryInterval time.Duration
+}
+
+// Health describes the state of a Cache for monitoring.
+type Health struct {
+	Status              string     `json:"status"`
+	Source              Source     `json:"source"`
+	BreedCount          int        `json:"breed_count"`
+	UpdatedAt           *time.Time `json:"updated_at,omitempty"`
+	LastAttemptAt       *time.Time `json:"last_attempt_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Circuit             string     `json:"circuit,omitempty"`
+}
+
+// Cache holds the breed list in memory. It always has something to serve:
+// the on-disk snapshot or the bundled list until the provider answers.
+type Cache struct {
+	provider BreedProvider
+	opts     CacheOptions
+	logger   *slog.Logger
+
+	mu          sync.RWMutex
+	breeds      []Breed
+	source      Source
+	updatedAt   time.Time
+	lastAttempt time.Time
+	lastErr     error
+	failures    int
+}
+
+type snapshot struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Breeds    []Breed   `json:"breeds"`
 }
 
-func updateBreeds() {
-    breeds, err := FetchBreeds()
-    if err != nil {
-        return
-    }
+func NewCache(provider BreedProvider, opts CacheOptions, logger *slog.Logger) *Cache {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = 24 * time.Hour
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = time.Minute
+	}
+	if opts.RetryInterval > opts.RefreshInterval {
+		opts.RetryInterval = opts.RefreshInterval
+	}
 
-    cacheMutex.Lock()
-    breedCache = breeds
-    cacheMutex.Unlock()
+	return &Cache{
+		provider: provider,
+		opts:     opts,
+		logger:   logger.With(slog.String("component", "breeds")),
+	}
 }
 
-func IsValidBreed(breed string) bool {
-    cacheMutex.RLock()
-    defer cacheMutex.RUnlock()
+// Start loads the snapshot, or the bundled list when there is none, and
+// then keeps the cache fresh from the provider in the background until ctx
+// is cancelled.
+func (c *Cache) Start(ctx context.Context) {
+	c.loadFallback()
+	go c.run(ctx)
+}
 
-    for _, b := range breedCache {
-        if b.Name == breed {
-            return true
-        }
-    }
-    return false
+func (c *Cache) Breeds() []Breed {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.breeds
 }
 
-func GetBreeds() []Breed {
-	cacheMutex.RLock()
-	defer cacheMutex.RUnlock()
-	return breedCache
+// Health reports "ok" while the list comes from the provider and the last
+// refresh succeeded, and "degraded" otherwise.
+func (c *Cache) Health() Health {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	h := Health{
+		Status:              "ok",
+		Source:              c.source,
+		BreedCount:          len(c.breeds),
+		ConsecutiveFailures: c.failures,
+	}
+	if c.source != SourceAPI || c.failures > 0 {
+		h.Status = "degraded"
+	}
+	if !c.updatedAt.IsZero() {
+		updatedAt := c.updatedAt
+		h.UpdatedAt = &updatedAt
+	}
+	if !c.lastAttempt.IsZero() {
+		lastAttempt := c.lastAttempt
+		h.LastAttemptAt = &lastAttempt
+	}
+	if c.lastErr != nil {
+		h.LastError = c.lastErr.Error()
+	}
+	if p, ok := c.provider.(interface{ CircuitState() string }); ok {
+		h.Circuit = p.CircuitState()
+	}
+	return h
+}
+
+func (c *Cache) run(ctx context.Context) {
+	for {
+		delay := c.opts.RefreshInterval
+		if err := c.Refresh(ctx); err != nil {
+			delay = c.retryDelay()
+			c.logger.Warn("failed to refresh breeds",
+				slog.Any("error", err),
+				slog.Duration("retry_in", delay),
+			)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Refresh fetches the breed list from the provider and, on success, replaces
+// the cached list and writes a new snapshot.
+func (c *Cache) Refresh(ctx context.Context) error {
+	const op = "breeds.Cache.Refresh"
+
+	breeds, err := c.provider.FetchBreeds(ctx)
+
+	c.mu.Lock()
+	c.lastAttempt = time.Now()
+	c.lastErr = err
+	if err != nil {
+		c.failures++
+		c.mu.Unlock()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	c.failures = 0
+	c.breeds = breeds
+	c.source = SourceAPI
+	c.updatedAt = c.lastAttempt
+	fetchedAt := c.updatedAt
+	c.mu.Unlock()
+
+	c.logger.Info("breeds refreshed", slog.Int("count", len(breeds)))
+
+	if err := c.saveSnapshot(snapshot{FetchedAt: fetchedAt, Breeds: breeds}); err != nil {
+		c.logger.Warn("failed to save breed snapshot", slog.Any("error", err))
+	}
+	return nil
+}
+
+func (c *Cache) retryDelay() time.Duration {
+	c.mu.RLock()
+	failures := c.failures
+	c.mu.RUnlock()
+
+	delay := c.opts.RetryInterval
+	for i := 1; i < failures && delay < c.opts.RefreshInterval; i++ {
+		delay *= 2
+	}
+	if delay > c.opts.RefreshInterval {
+		delay = c.opts.RefreshInterval
+	}
+	return delay
+}
+
+func (c *Cache) loadFallback() {
+	snap, err := c.loadSnapshot()
+	if err == nil && len(snap.Breeds) > 0 {
+		c.set(snap.Breeds, SourceSnapshot, snap.FetchedAt)
+		c.logger.Info("loaded breed snapshot",
+			slog.Int("count", len(snap.Breeds)),
+			slog.Time("fetched_at", snap.FetchedAt),
+		)
+		return
+	}
+	if err != nil && !os.IsNotExist(err) {
+		c.logger.Warn("failed to load breed snapshot", slog.Any("error", err))
+	}
+
+	c.set(bundledBreeds(), SourceBundled, time.Time{})
+	c.logger.Info("using bundled breed list", slog.Int("count", len(c.Breeds())))
+}
+
+func (c *Cache) set(breeds []Breed, source Source, updatedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.breeds = breeds
+	c.source = source
+	c.updatedAt = updatedAt
+}
+
+func (c *Cache) loadSnapshot() (snapshot, error) {
+	var snap snapshot
+	if c.opts.SnapshotPath == "" {
+		return snap, os.ErrNotExist
+	}
+
+	data, err := os.ReadFile(c.opts.SnapshotPath)
+	if err != nil {
+		return snap, err
+	}
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return snap, fmt.Errorf("decoding %s: %w", c.opts.SnapshotPath, err)
+	}
+	return snap, nil
+}
+
+// saveSnapshot writes to a temporary file and renames it into place, so a
+// crash mid-write never leaves a truncated snapshot behind.
+func (c *Cache) saveSnapshot(snap snapshot) error {
+	if c.opts.SnapshotPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(c.opts.SnapshotPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".breeds-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.opts.SnapshotPath)
 }
@@ -0,0 +1,155 @@
+package breeds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// stubProvider returns breeds, or err when it is set.
+type stubProvider struct {
+	breeds []Breed
+	err    error
+}
+
+func (p *stubProvider) FetchBreeds(ctx context.Context) ([]Breed, error) {
+	return p.breeds, p.err
+}
+
+func resolves(c *Cache, name string) bool {
+	_, ok := c.Resolve(name)
+	return ok
+}
+
+func writeSnapshot(t *testing.T, path string, snap snapshot) {
+	t.Helper()
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheFallsBackToBundled(t *testing.T) {
+	c := NewCache(&stubProvider{err: errors.New("down")}, CacheOptions{
+		SnapshotPath: filepath.Join(t.TempDir(), "missing.json"),
+	}, discardLogger)
+	c.loadFallback()
+
+	if len(c.Breeds()) != len(bundledBreeds()) {
+		t.Errorf("cache holds %d breeds, want the %d bundled ones", len(c.Breeds()), len(bundledBreeds()))
+	}
+	h := c.Health()
+	if h.Source != SourceBundled || h.Status != "degraded" || h.UpdatedAt != nil {
+		t.Errorf("health = %+v, want a degraded bundled source", h)
+	}
+}
+
+func TestCacheFallsBackToBundledOnCorruptSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breeds.json")
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCache(&stubProvider{}, CacheOptions{SnapshotPath: path}, discardLogger)
+	c.loadFallback()
+
+	if c.Health().Source != SourceBundled {
+		t.Errorf("source %s, want %s", c.Health().Source, SourceBundled)
+	}
+}
+
+func TestCachePrefersSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breeds.json")
+	fetchedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	writeSnapshot(t, path, snapshot{FetchedAt: fetchedAt, Breeds: []Breed{{ID: "beng", Name: "Bengal"}}})
+
+	c := NewCache(&stubProvider{err: errors.New("down")}, CacheOptions{SnapshotPath: path}, discardLogger)
+	c.loadFallback()
+
+	if !resolves(c, "Bengal") || resolves(c, "Abyssinian") {
+		t.Errorf("breeds = %v, want only the snapshot", c.Breeds())
+	}
+	h := c.Health()
+	if h.Source != SourceSnapshot || h.Status != "degraded" {
+		t.Errorf("health = %+v, want a degraded snapshot source", h)
+	}
+	if h.UpdatedAt == nil || !h.UpdatedAt.Equal(fetchedAt) {
+		t.Errorf("updated at %v, want %v", h.UpdatedAt, fetchedAt)
+	}
+}
+
+func TestCacheRefresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breeds.json")
+	provider := &stubProvider{breeds: []Breed{{ID: "beng", Name: "Bengal"}}}
+
+	c := NewCache(provider, CacheOptions{SnapshotPath: path}, discardLogger)
+	c.loadFallback()
+
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	h := c.Health()
+	if h.Status != "ok" || h.Source != SourceAPI || h.BreedCount != 1 || h.LastError != "" {
+		t.Errorf("health = %+v, want ok from the api", h)
+	}
+
+	// A new cache starts from the snapshot the refresh wrote.
+	restarted := NewCache(&stubProvider{err: errors.New("down")}, CacheOptions{SnapshotPath: path}, discardLogger)
+	restarted.loadFallback()
+	if restarted.Health().Source != SourceSnapshot || !resolves(restarted, "Bengal") {
+		t.Errorf("restarted cache = %+v, want the saved snapshot", restarted.Health())
+	}
+
+	// A failed refresh keeps the list and reports the error.
+	provider.err = errors.New("down")
+	if err := c.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh succeeded with a failing provider")
+	}
+	h = c.Health()
+	if h.Status != "degraded" || h.ConsecutiveFailures != 1 || h.LastError == "" || !resolves(c, "Bengal") {
+		t.Errorf("health = %+v, want degraded with the last list kept", h)
+	}
+}
+
+func TestCacheRetryDelay(t *testing.T) {
+	c := NewCache(&stubProvider{err: errors.New("down")}, CacheOptions{
+		RefreshInterval: time.Hour,
+		RetryInterval:   time.Minute,
+	}, discardLogger)
+
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute}
+	for i, delay := range want {
+		c.Refresh(context.Background())
+		if got := c.retryDelay(); got != delay {
+			t.Errorf("after %d failure(s) retry in %v, want %v", i+1, got, delay)
+		}
+	}
+
+	for range 10 {
+		c.Refresh(context.Background())
+	}
+	if got := c.retryDelay(); got != time.Hour {
+		t.Errorf("retry in %v, want it capped at %v", got, time.Hour)
+	}
+}
+
+func TestCacheHealthReportsCircuit(t *testing.T) {
+	p := NewHTTPProvider(HTTPProviderOptions{})
+	c := NewCache(p, CacheOptions{}, discardLogger)
+
+	if got := c.Health().Circuit; got != "closed" {
+		t.Errorf("circuit %q, want closed", got)
+	}
+}
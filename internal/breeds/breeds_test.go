@@ -0,0 +1,233 @@
+package breeds
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+const breedsJSON = `[{"id":"abys","name":"Abyssinian"},{"id":"beng","name":"Bengal"}]`
+
+// upstream is a breed API whose answer to each request is decided by
+// respond, called with the 1-based number of the request.
+type upstream struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []time.Time
+}
+
+func newUpstream(t *testing.T, respond func(n int, w http.ResponseWriter, r *http.Request)) *upstream {
+	t.Helper()
+
+	u := &upstream{}
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u.mu.Lock()
+		u.requests = append(u.requests, time.Now())
+		n := len(u.requests)
+		u.mu.Unlock()
+
+		respond(n, w, r)
+	}))
+	t.Cleanup(u.Close)
+	return u
+}
+
+func (u *upstream) count() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.requests)
+}
+
+func (u *upstream) gaps() []time.Duration {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var gaps []time.Duration
+	for i := 1; i < len(u.requests); i++ {
+		gaps = append(gaps, u.requests[i].Sub(u.requests[i-1]))
+	}
+	return gaps
+}
+
+func serveBreeds(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(breedsJSON))
+}
+
+func TestFetchBreedsRetriesServerErrors(t *testing.T) {
+	api := newUpstream(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		if n < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		serveBreeds(w)
+	})
+
+	const backoff = 20 * time.Millisecond
+	p := NewHTTPProvider(HTTPProviderOptions{URL: api.URL, Retries: 2, Backoff: backoff})
+
+	breeds, err := p.FetchBreeds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(breeds) != 2 || breeds[1].Name != "Bengal" {
+		t.Errorf("breeds = %v", breeds)
+	}
+	if api.count() != 3 {
+		t.Fatalf("made %d requests, want 3", api.count())
+	}
+
+	// The delay doubles after every failed attempt.
+	gaps := api.gaps()
+	if gaps[0] < backoff || gaps[1] < 2*backoff {
+		t.Errorf("delays between attempts %v, want at least %v and %v", gaps, backoff, 2*backoff)
+	}
+	if p.CircuitState() != "closed" {
+		t.Errorf("circuit %s after a success, want closed", p.CircuitState())
+	}
+}
+
+func TestFetchBreedsGivesUpAfterRetries(t *testing.T) {
+	api := newUpstream(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	p := NewHTTPProvider(HTTPProviderOptions{URL: api.URL, Retries: 2, Backoff: time.Millisecond})
+
+	if _, err := p.FetchBreeds(context.Background()); err == nil {
+		t.Fatal("FetchBreeds succeeded against a failing API")
+	}
+	if api.count() != 3 {
+		t.Errorf("made %d requests, want 3", api.count())
+	}
+}
+
+func TestFetchBreedsDoesNotRetryClientErrors(t *testing.T) {
+	api := newUpstream(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	p := NewHTTPProvider(HTTPProviderOptions{URL: api.URL, Retries: 2, Backoff: time.Millisecond})
+
+	if _, err := p.FetchBreeds(context.Background()); err == nil {
+		t.Fatal("FetchBreeds succeeded against a rejecting API")
+	}
+	if api.count() != 1 {
+		t.Errorf("made %d requests, want 1", api.count())
+	}
+}
+
+func TestFetchBreedsRetriesTimeouts(t *testing.T) {
+	api := newUpstream(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		if n == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		serveBreeds(w)
+	})
+
+	p := NewHTTPProvider(HTTPProviderOptions{
+		URL:     api.URL,
+		Timeout: 50 * time.Millisecond,
+		Retries: 1,
+		Backoff: time.Millisecond,
+	})
+
+	if _, err := p.FetchBreeds(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if api.count() != 2 {
+		t.Errorf("made %d requests, want 2", api.count())
+	}
+}
+
+func TestFetchBreedsStopsOnCancel(t *testing.T) {
+	api := newUpstream(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	p := NewHTTPProvider(HTTPProviderOptions{URL: api.URL, Retries: 5, Backoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := p.FetchBreeds(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FetchBreeds = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if api.count() != 1 {
+		t.Errorf("made %d requests, want 1", api.count())
+	}
+}
+
+func TestFetchBreedsCircuitBreaker(t *testing.T) {
+	var healthy bool
+	var mu sync.Mutex
+	api := newUpstream(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !healthy {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		serveBreeds(w)
+	})
+
+	const cooldown = 50 * time.Millisecond
+	p := NewHTTPProvider(HTTPProviderOptions{
+		URL:              api.URL,
+		Backoff:          time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  cooldown,
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.FetchBreeds(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: got %v, want an upstream error", i+1, err)
+		}
+	}
+	if p.CircuitState() != "open" {
+		t.Fatalf("circuit %s after %d failures, want open", p.CircuitState(), 2)
+	}
+
+	if _, err := p.FetchBreeds(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("call while open: got %v, want %v", err, ErrCircuitOpen)
+	}
+	if api.count() != 2 {
+		t.Fatalf("made %d requests, want the open circuit to skip the API", api.count())
+	}
+
+	// After the cooldown a trial call is let through; a failure re-opens
+	// the circuit for another cooldown.
+	time.Sleep(cooldown)
+	if p.CircuitState() != "half-open" {
+		t.Fatalf("circuit %s after the cooldown, want half-open", p.CircuitState())
+	}
+	if _, err := p.FetchBreeds(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("trial call: got %v, want an upstream error", err)
+	}
+	if p.CircuitState() != "open" {
+		t.Fatalf("circuit %s after a failed trial, want open", p.CircuitState())
+	}
+
+	// A successful trial closes it again.
+	mu.Lock()
+	healthy = true
+	mu.Unlock()
+	time.Sleep(cooldown)
+	if _, err := p.FetchBreeds(ctx); err != nil {
+		t.Fatalf("trial call: %v", err)
+	}
+	if p.CircuitState() != "closed" {
+		t.Errorf("circuit %s after a successful trial, want closed", p.CircuitState())
+	}
+}
@@ -0,0 +1,20 @@
+package breeds
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+// bundledJSON is the last-resort breed list, used when neither the provider
+// nor a snapshot is available. Refresh it from TheCatAPI when breeds change.
+//
+//go:embed bundled_breeds.json
+var bundledJSON []byte
+
+func bundledBreeds() []Breed {
+	var breeds []Breed
+	if err := json.Unmarshal(bundledJSON, &breeds); err != nil {
+		panic("breeds: invalid bundled_breeds.json: " + err.Error())
+	}
+	return breeds
+}
@@ -0,0 +1,174 @@
+package breeds
+
+import (
+	"sort"
+	"strings"
+)
+
+// Normalize lowercases s and collapses runs of whitespace, so " Maine  coon"
+// and "maine coon" compare equal.
+func Normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// Resolve finds the breed named by s, which may be a breed ID such as "abys"
+// or a breed name. The comparison ignores case and extra whitespace.
+func (c *Cache) Resolve(s string) (Breed, bool) {
+	q := Normalize(s)
+	if q == "" {
+		return Breed{}, false
+	}
+
+	for _, b := range c.Breeds() {
+		if strings.ToLower(b.ID) == q || Normalize(b.Name) == q {
+			return b, true
+		}
+	}
+	return Breed{}, false
+}
+
+// Breed returns the breed with the given ID.
+func (c *Cache) Breed(id string) (Breed, bool) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	for _, b := range c.Breeds() {
+		if strings.ToLower(b.ID) == id {
+			return b, true
+		}
+	}
+	return Breed{}, false
+}
+
+// Suggest returns up to limit breeds whose names are close to s by edit
+// distance, closest first.
+func (c *Cache) Suggest(s string, limit int) []Breed {
+	q := Normalize(s)
+	if q == "" {
+		return nil
+	}
+
+	var matches []scored
+	for _, b := range c.Breeds() {
+		if d := distance(q, b); d <= maxDistance(q) {
+			matches = append(matches, scored{breed: b, score: d})
+		}
+	}
+	return top(matches, limit)
+}
+
+// Search returns the breeds matching q. Exact matches come first, then
+// breeds whose name or one of its words starts with q, then names
+// containing q, then names within a small edit distance. An empty query
+// returns every breed.
+func (c *Cache) Search(q string) []Breed {
+	q = Normalize(q)
+	all := c.Breeds()
+	if q == "" {
+		return all
+	}
+
+	var matches []scored
+	for _, b := range all {
+		name := Normalize(b.Name)
+		switch {
+		case name == q || strings.ToLower(b.ID) == q:
+			matches = append(matches, scored{breed: b, score: 0})
+		case strings.HasPrefix(name, q):
+			matches = append(matches, scored{breed: b, score: 1})
+		case hasWordPrefix(name, q):
+			matches = append(matches, scored{breed: b, score: 2})
+		case strings.Contains(name, q):
+			matches = append(matches, scored{breed: b, score: 3})
+		default:
+			if d := distance(q, b); d <= maxDistance(q) {
+				matches = append(matches, scored{breed: b, score: 4 + d})
+			}
+		}
+	}
+	return top(matches, 0)
+}
+
+type scored struct {
+	breed Breed
+	score int
+}
+
+// top sorts matches by score, then name, and keeps the first limit of them.
+// A limit of zero keeps all matches.
+func top(matches []scored, limit int) []Breed {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return matches[i].breed.Name < matches[j].breed.Name
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	breeds := make([]Breed, 0, len(matches))
+	for _, m := range matches {
+		breeds = append(breeds, m.breed)
+	}
+	return breeds
+}
+
+func hasWordPrefix(name, q string) bool {
+	for _, word := range strings.Fields(name) {
+		if strings.HasPrefix(word, q) {
+			return true
+		}
+	}
+	return false
+}
+
+// distance is the smallest edit distance between q and the breed's name or
+// ID. Each word of q is also compared on its own, so "siamese cat" is close
+// to "Siamese".
+func distance(q string, b Breed) int {
+	name := Normalize(b.Name)
+	best := levenshtein(q, name)
+	if d := levenshtein(q, strings.ToLower(b.ID)); d < best {
+		best = d
+	}
+	if words := strings.Fields(q); len(words) > 1 {
+		for _, word := range words {
+			if len(word) < 4 {
+				continue
+			}
+			if d := levenshtein(word, name); d < best {
+				best = d
+			}
+		}
+	}
+	return best
+}
+
+// maxDistance is how many edits still count as a likely typo of q.
+func maxDistance(q string) int {
+	if d := len([]rune(q)) / 3; d > 2 {
+		return d
+	}
+	return 2
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
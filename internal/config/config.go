@@ -8,18 +8,149 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+// EnvLocal is the env of a developer's machine, where some safeguards for
+// shared deployments are relaxed.
+const EnvLocal = "local"
+
 type Config struct {
     Env         string `yaml:"env" env-default:"development"`
     StoragePath string `yaml:"storage_path" env-required:"true"`
+    // MigrationsPath overrides the migrations embedded in the binary with
+    // a directory on disk. Leave empty outside of development.
+    MigrationsPath string `yaml:"migrations_path"`
     HTTPServer  `yaml:"http_server"`
+    Rules       Rules `yaml:"rules"`
+    QueryTimeouts QueryTimeouts `yaml:"query_timeouts"`
+    Breeds      Breeds `yaml:"breeds"`
+    Matching    Matching `yaml:"matching"`
+    Scheduler   Scheduler `yaml:"scheduler"`
+    Auth        Auth `yaml:"auth"`
+    Encryption  Encryption `yaml:"encryption"`
+    Attachments Attachments `yaml:"attachments"`
+}
+
+// Attachments configures files attached to targets. Path is the directory
+// their content is kept in, next to the database when empty. MaxSize is in
+// bytes, and AllowedTypes lists the accepted media types as detected from
+// the content. Zero values fall back to the defaults in the service
+// package.
+type Attachments struct {
+    Path         string   `yaml:"path"`
+    MaxSize      int64    `yaml:"max_size"`
+    AllowedTypes []string `yaml:"allowed_types"`
+}
+
+// Encryption configures encryption of target notes at rest. Keys maps key
+// IDs to base64 encoded 32-byte key-encryption keys, and new notes are
+// sealed with ActiveKey. Keys that notes may still be sealed with must stay
+// listed until rotate-keys has re-encrypted them. Setting ActiveKey enables
+// encryption, and outside the local env the server then refuses to start
+// without the key. Without ActiveKey, notes are stored in plain text.
+type Encryption struct {
+    ActiveKey string            `yaml:"active_key"`
+    // Keys may be left out of the file: the NOTES_KEK environment variable
+    // supplies the material of the active key.
+    Keys      map[string]string `yaml:"keys"`
+}
+
+// Auth lists the principals allowed to call the API. Callers identify
+// themselves with the X-API-Key header; callers without a key get
+// AnonymousClearance, "public" when empty.
+type Auth struct {
+    AnonymousClearance string      `yaml:"anonymous_clearance"`
+    Principals         []Principal `yaml:"principals"`
 }
 
+// Principal is an API caller cleared to read missions up to Clearance:
+// public, confidential, secret or top-secret.
+type Principal struct {
+    Name      string `yaml:"name"`
+    APIKey    string `yaml:"api_key"`
+    Clearance string `yaml:"clearance"`
+}
+
+// HTTPServer configures the HTTP server. Timeout is the deadline of a
+// request's handling; ReadTimeout bounds reading the whole request,
+// body included, so it must leave room for the largest attachment upload.
 type HTTPServer struct {
     Address     string        `yaml:"address" env-default:"0.0.0.0:8080"`
     Timeout     time.Duration `yaml:"timeout" env-default:"5s"`
+    ReadTimeout time.Duration `yaml:"read_timeout" env-default:"5m"`
     IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
 }
 
+// QueryTimeouts bounds how long storage operations may run. Operations maps
+// an operation name such as "storage.sqlite.GetAllMissions" to its own
+// deadline; every other operation uses Default.
+type QueryTimeouts struct {
+    Default    time.Duration            `yaml:"default"`
+    Operations map[string]time.Duration `yaml:"operations"`
+}
+
+// Breeds configures where the breed list comes from and how it is kept
+// fresh. Zero values fall back to the defaults in the breeds package.
+type Breeds struct {
+    URL              string        `yaml:"url"`
+    // APIKey is sent as x-api-key. The CAT_API_KEY environment variable
+    // takes precedence, so the key does not have to live in the file.
+    APIKey           string        `yaml:"api_key"`
+    Timeout          time.Duration `yaml:"timeout"`
+    Retries          int           `yaml:"retries"`
+    Backoff          time.Duration `yaml:"backoff"`
+    BreakerThreshold int           `yaml:"breaker_threshold"`
+    BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`
+    RefreshInterval  time.Duration `yaml:"refresh_interval"`
+    RetryInterval    time.Duration `yaml:"retry_interval"`
+    SnapshotPath     string        `yaml:"snapshot_path"`
+}
+
+// Matching configures how cats are ranked as mission candidates. Zero
+// values fall back to the defaults in the matching package.
+type Matching struct {
+    Weights            MatchingWeights `yaml:"weights"`
+    // ExperienceCapYears is the experience at which a cat gets the full
+    // experience score.
+    ExperienceCapYears int             `yaml:"experience_cap_years"`
+    // BreedTraits lists the breed trait levels averaged into the breed
+    // score, e.g. "intelligence" or "energy_level".
+    BreedTraits        []string        `yaml:"breed_traits"`
+}
+
+// Scheduler configures background jobs. Zero values fall back to the
+// defaults in the scheduler package.
+type Scheduler struct {
+    // OverdueCheckInterval is how often missions past their due time are
+    // looked for.
+    OverdueCheckInterval time.Duration `yaml:"overdue_check_interval"`
+}
+
+type MatchingWeights struct {
+    Experience         float64 `yaml:"experience"`
+    CompletionRate     float64 `yaml:"completion_rate"`
+    CountryFamiliarity float64 `yaml:"country_familiarity"`
+    BreedTraits        float64 `yaml:"breed_traits"`
+    SalaryCost         float64 `yaml:"salary_cost"`
+}
+
+// Rules holds the business limits applied to missions. Agency divisions run
+// with different limits, so they are read from config rather than hard-coded.
+// Omitted limits fall back to the defaults in the rules package; a limit set
+// to 0 is kept as 0, so min_targets: 0 allows missions without targets.
+type Rules struct {
+    MinTargets              *int                `yaml:"min_targets"`
+    MaxTargets              *int                `yaml:"max_targets"`
+    MaxActiveMissionsPerCat *int                `yaml:"max_active_missions_per_cat"`
+    CountryExperience       []CountryExperience `yaml:"country_experience"`
+    FreezeNotesOnComplete   *bool               `yaml:"freeze_notes_on_complete"`
+}
+
+// CountryExperience requires a minimum number of years of experience from
+// any cat assigned to a mission with a target in one of Countries.
+type CountryExperience struct {
+    Countries []string `yaml:"countries"`
+    MinYears  int      `yaml:"min_years"`
+}
+
 func MustLoad() *Config {
     configPath := os.Getenv("CONFIG_PATH")
     if configPath == "" {
@@ -37,6 +168,17 @@ func MustLoad() *Config {
         log.Fatalf("error reading config file: %s", err)
     }
 
+    if apiKey := os.Getenv("CAT_API_KEY"); apiKey != "" {
+        cfg.Breeds.APIKey = apiKey
+    }
+
+    if kek := os.Getenv("NOTES_KEK"); kek != "" {
+        if cfg.Encryption.Keys == nil {
+            cfg.Encryption.Keys = make(map[string]string)
+        }
+        cfg.Encryption.Keys[cfg.Encryption.ActiveKey] = kek
+    }
+
     return &cfg
 }
 
@@ -0,0 +1,67 @@
+// Package countries resolves country names and codes to ISO 3166-1 alpha-2
+// codes, using a dataset embedded into the binary.
+package countries
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+)
+
+// Country is an ISO 3166-1 country. Name is its short display name, and
+// Aliases lists other names it is known by, such as its official name.
+type Country struct {
+	Alpha2  string   `json:"alpha2"`
+	Alpha3  string   `json:"alpha3"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// countriesJSON is generated from the iso-codes ISO 3166-1 data, with
+// common names and aliases added by hand.
+//
+//go:embed countries.json
+var countriesJSON []byte
+
+var (
+	byCode = make(map[string]Country)
+	byName = make(map[string]Country)
+)
+
+func init() {
+	var countries []Country
+	if err := json.Unmarshal(countriesJSON, &countries); err != nil {
+		panic("countries: invalid countries.json: " + err.Error())
+	}
+
+	for _, c := range countries {
+		byCode[c.Alpha2] = c
+		for _, key := range append([]string{c.Alpha2, c.Alpha3, c.Name}, c.Aliases...) {
+			key = normalize(key)
+			if other, ok := byName[key]; ok && other.Alpha2 != c.Alpha2 {
+				panic("countries: " + key + " names both " + other.Alpha2 + " and " + c.Alpha2)
+			}
+			byName[key] = c
+		}
+	}
+}
+
+// Lookup finds the country named by s, which may be an alpha-2 or alpha-3
+// code, a name or a common alias such as "UK". The comparison ignores case,
+// dots, extra whitespace and a leading "the".
+func Lookup(s string) (Country, bool) {
+	c, ok := byName[normalize(s)]
+	return c, ok
+}
+
+// Name returns the display name of the country with the given alpha-2 code,
+// or "" when the code is unknown.
+func Name(alpha2 string) string {
+	return byCode[alpha2].Name
+}
+
+func normalize(s string) string {
+	s = strings.ToLower(strings.ReplaceAll(s, ".", ""))
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.TrimPrefix(s, "the ")
+}
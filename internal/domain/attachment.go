@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Attachment is a file attached to a target, such as a photo or a document.
+// Its content is kept in blob storage under SHA256.
+type Attachment struct {
+	ID         int64     `json:"id"`
+	TargetID   int64     `json:"target_id"`
+	Filename   string    `json:"filename"`
+	MediaType  string    `json:"media_type"`
+	Size       int64     `json:"size"`
+	SHA256     string    `json:"sha256"`
+	UploadedBy string    `json:"uploaded_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// AuditAction names a change recorded in the audit log.
+type AuditAction string
+
+const (
+	// AuditTargetMoved records a target moving to another mission. The
+	// entry's MissionID is the mission it moved to, and its details hold
+	// from_mission_id and to_mission_id.
+	AuditTargetMoved AuditAction = "target.moved"
+)
+
+// AuditEntry is one change in the audit log. Actor is the name of the
+// principal that made it, empty for anonymous callers.
+type AuditEntry struct {
+	ID        int64          `json:"id"`
+	Action    AuditAction    `json:"action"`
+	Actor     string         `json:"actor,omitempty"`
+	MissionID *int64         `json:"mission_id,omitempty"`
+	TargetID  *int64         `json:"target_id,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
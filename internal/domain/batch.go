@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BatchMode decides what happens to a batch when some of its items fail.
+type BatchMode string
+
+const (
+	// BatchAtomic applies every item or, when any fails, none of them.
+	BatchAtomic BatchMode = "atomic"
+	// BatchPartial applies the items that succeed and reports the others.
+	BatchPartial BatchMode = "partial"
+)
+
+// ErrBatchAborted is the result of an item that succeeded on its own but
+// was rolled back because another item of its atomic batch failed.
+var ErrBatchAborted = errors.New("not applied because another item of the batch failed")
+
+// ParseBatchMode returns the batch mode named by s, atomic when s is empty.
+func ParseBatchMode(s string) (BatchMode, error) {
+	switch mode := BatchMode(s); mode {
+	case "":
+		return BatchAtomic, nil
+	case BatchAtomic, BatchPartial:
+		return mode, nil
+	default:
+		return "", &ValidationError{Err: fmt.Errorf("mode must be %s or %s", BatchAtomic, BatchPartial)}
+	}
+}
+
+// BatchResult is the outcome of the item at Index of a batch. ID is the
+// entity the item created or names, and Err is nil when it succeeded.
+type BatchResult struct {
+	Index int
+	ID    int64
+	Err   error
+}
+
+// BatchFailed reports whether any item of a batch failed.
+func BatchFailed(results []BatchResult) bool {
+	for _, r := range results {
+		if r.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// MissionUpdate is an item of a batch of mission updates. Exactly one of
+// Complete and CatID is set: the new completion state, or the cat to assign.
+type MissionUpdate struct {
+	ID       int64
+	Complete *bool
+	CatID    *int64
+}
@@ -0,0 +1,28 @@
+package domain
+
+// Breed is a breed record as published by TheCatAPI. Trait levels range
+// from 1 to 5; zero means the source did not provide a value.
+type Breed struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	Description      string       `json:"description,omitempty"`
+	Temperament      string       `json:"temperament,omitempty"`
+	Origin           string       `json:"origin,omitempty"`
+	CountryCode      string       `json:"country_code,omitempty"`
+	LifeSpan         string       `json:"life_span,omitempty"`
+	Weight           *BreedWeight `json:"weight,omitempty"`
+	Adaptability     int          `json:"adaptability,omitempty"`
+	AffectionLevel   int          `json:"affection_level,omitempty"`
+	EnergyLevel      int          `json:"energy_level,omitempty"`
+	Intelligence     int          `json:"intelligence,omitempty"`
+	SocialNeeds      int          `json:"social_needs,omitempty"`
+	StrangerFriendly int          `json:"stranger_friendly,omitempty"`
+	Vocalisation     int          `json:"vocalisation,omitempty"`
+	WikipediaURL     string       `json:"wikipedia_url,omitempty"`
+}
+
+// BreedWeight is a weight range such as "3 - 5", in pounds and kilograms.
+type BreedWeight struct {
+	Imperial string `json:"imperial"`
+	Metric   string `json:"metric"`
+}
@@ -0,0 +1,47 @@
+// Package domain holds the entities shared by the service, storage and
+// transport layers.
+package domain
+
+import "time"
+
+type SpyCat struct {
+	ID                int64     `json:"id"`
+	Name              string    `json:"name" validate:"required,min=1,max=100"`
+	YearsOfExperience int       `json:"years_of_experience" validate:"min=0"`
+	Breed             string    `json:"breed" validate:"required,min=1,max=100"`
+	BreedID           string    `json:"breed_id,omitempty"`
+	Salary            float64   `json:"salary" validate:"required,gt=0"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// SpyCatProfile is a cat with its breed record expanded in place of the
+// breed name.
+type SpyCatProfile struct {
+	SpyCat
+	Breed *Breed `json:"breed"`
+}
+
+// CatTrackRecord summarises the missions a cat is assigned to. Countries
+// lists the distinct target countries of those missions.
+type CatTrackRecord struct {
+	CatID             int64    `json:"cat_id"`
+	Missions          int      `json:"missions"`
+	CompletedMissions int      `json:"completed_missions"`
+	ActiveMissions    int      `json:"active_missions"`
+	Countries         []string `json:"countries"`
+}
+
+// CatStats summarises what a cat has achieved on its missions. Average
+// completion time only covers missions whose assignment and completion times
+// were both recorded.
+type CatStats struct {
+	CatID                        int64    `json:"cat_id"`
+	MissionsAssigned             int      `json:"missions_assigned"`
+	MissionsCompleted            int      `json:"missions_completed"`
+	TargetsCompleted             int      `json:"targets_completed"`
+	AverageTimeToComplete        string   `json:"average_time_to_complete,omitempty"`
+	AverageTimeToCompleteSeconds *float64 `json:"average_time_to_complete_seconds,omitempty"`
+	Countries                    []string `json:"countries"`
+	CurrentMissions              []int64  `json:"current_missions"`
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Priority is how urgent a mission is.
+type Priority string
+
+const (
+	PriorityLow      Priority = "low"
+	PriorityNormal   Priority = "normal"
+	PriorityHigh     Priority = "high"
+	PriorityCritical Priority = "critical"
+)
+
+// Priorities lists every priority from least to most urgent.
+var Priorities = []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityCritical}
+
+// ParsePriority returns the priority named by s.
+func ParsePriority(s string) (Priority, error) {
+	for _, p := range Priorities {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	return "", &ValidationError{Err: fmt.Errorf("priority must be one of %s", joinLevels(Priorities))}
+}
+
+// Classification is how sensitive a mission is. Reading the notes of a
+// mission requires a clearance at or above its classification.
+type Classification string
+
+const (
+	ClassificationPublic       Classification = "public"
+	ClassificationConfidential Classification = "confidential"
+	ClassificationSecret       Classification = "secret"
+	ClassificationTopSecret    Classification = "top-secret"
+)
+
+// Classifications lists every classification from least to most sensitive.
+var Classifications = []Classification{ClassificationPublic, ClassificationConfidential, ClassificationSecret, ClassificationTopSecret}
+
+// ParseClassification returns the classification named by s.
+func ParseClassification(s string) (Classification, error) {
+	for _, c := range Classifications {
+		if string(c) == s {
+			return c, nil
+		}
+	}
+	return "", &ValidationError{Err: fmt.Errorf("classification must be one of %s", joinLevels(Classifications))}
+}
+
+// Covers reports whether a principal cleared to c may read a mission
+// classified as other.
+func (c Classification) Covers(other Classification) bool {
+	return level(Classifications, c) >= level(Classifications, other)
+}
+
+func level[T comparable](levels []T, v T) int {
+	for i, l := range levels {
+		if l == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func joinLevels[T ~string](levels []T) string {
+	names := make([]string, len(levels))
+	for i, l := range levels {
+		names[i] = string(l)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrCatNotFound      = errors.New("cat not found")
+	ErrMissionNotFound  = errors.New("mission not found")
+	ErrTargetNotFound   = errors.New("target not found")
+	ErrBreedNotFound    = errors.New("breed not found")
+	ErrRevisionNotFound = errors.New("note revision not found")
+	ErrTemplateNotFound = errors.New("mission template not found")
+
+	ErrAttachmentNotFound   = errors.New("attachment not found")
+	ErrAttachmentTooLarge   = errors.New("attachment is too large")
+	ErrUnsupportedMediaType = errors.New("unsupported attachment type")
+
+	ErrInvalidBreed      = errors.New("invalid breed")
+	ErrMissionComplete   = errors.New("mission is already complete")
+	ErrTargetComplete    = errors.New("target is already complete")
+	ErrMissionAssigned   = errors.New("mission is assigned to a cat")
+	ErrIncompleteTargets = errors.New("cannot complete mission until all targets are completed")
+	ErrNoCandidates      = errors.New("no cat is available for the mission")
+	ErrTemplateExists    = errors.New("a mission template with this name already exists")
+	ErrDeclassification  = errors.New("a target cannot move to a mission classified below its own")
+
+	ErrInsufficientClearance = errors.New("insufficient clearance for the mission classification")
+)
+
+// ValidationError reports input that does not satisfy the constraints of
+// a domain entity.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return "validation failed: " + e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// BreedError reports a breed that is not recognised, together with the
+// closest known breed names. It matches ErrInvalidBreed.
+type BreedError struct {
+	Breed       string
+	Suggestions []string
+}
+
+func (e *BreedError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("invalid breed %q", e.Breed)
+	}
+	return fmt.Sprintf("invalid breed %q, did you mean %s?", e.Breed, strings.Join(e.Suggestions, ", "))
+}
+
+func (e *BreedError) Unwrap() error {
+	return ErrInvalidBreed
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// TimeRange bounds a timestamp. Both bounds are exclusive and a nil bound
+// leaves that side open.
+type TimeRange struct {
+	After  *time.Time
+	Before *time.Time
+}
+
+// IsZero reports whether the range is open on both sides.
+func (r TimeRange) IsZero() bool {
+	return r.After == nil && r.Before == nil
+}
+
+// CatFilter narrows a list of cats. The zero value matches every cat.
+type CatFilter struct {
+	Created TimeRange
+}
+
+// TargetFilter narrows a list of targets. The zero value matches every
+// target.
+type TargetFilter struct {
+	Created TimeRange
+	// Countries, when not empty, keeps only targets in one of the listed
+	// countries, given as ISO 3166-1 alpha-2 codes.
+	Countries []string
+	Complete  *bool
+	MissionID *int64
+	// CatID keeps only targets of missions assigned to the cat.
+	CatID *int64
+}
+
+// AuditFilter narrows the audit log. The zero value matches every entry.
+type AuditFilter struct {
+	Action    AuditAction
+	MissionID *int64
+	TargetID  *int64
+}
+
+// MissionFilter narrows a list of missions. The zero value matches every
+// mission. A Completed bound only matches completed missions.
+type MissionFilter struct {
+	Created   TimeRange
+	Completed TimeRange
+	// Overdue, when set, keeps only missions that are (or are not) past
+	// their due time and still incomplete.
+	Overdue *bool
+	// Priorities and Classifications, when not empty, keep only missions
+	// with one of the listed values.
+	Priorities      []Priority
+	Classifications []Classification
+	Sort            MissionSort
+}
+
+// MissionSortField names what a list of missions can be ordered by.
+type MissionSortField string
+
+const (
+	SortByID             MissionSortField = "id"
+	SortByPriority       MissionSortField = "priority"
+	SortByClassification MissionSortField = "classification"
+	SortByDueAt          MissionSortField = "due_at"
+	SortByCreatedAt      MissionSortField = "created_at"
+)
+
+// MissionSortFields lists the fields missions can be sorted by.
+var MissionSortFields = []MissionSortField{SortByID, SortByPriority, SortByClassification, SortByDueAt, SortByCreatedAt}
+
+// MissionSort orders a list of missions. The zero value orders by ID.
+// Priorities and classifications sort by rank, not by name.
+type MissionSort struct {
+	Field MissionSortField
+	Desc  bool
+}
@@ -0,0 +1,17 @@
+package domain
+
+// ImportRecord is a cat or a mission read from an import; exactly one of
+// Cat and Mission is set. Line is where the record starts in line based
+// formats, zero otherwise, and Index is its position among the records of
+// its kind.
+//
+// The IDs of imported cats and missions only identify them within the
+// import: imported records are always created anew. A mission's CatID
+// names a cat of the same import when the import has a cat with that ID,
+// and an existing cat otherwise.
+type ImportRecord struct {
+	Line    int
+	Index   int
+	Cat     *SpyCat
+	Mission *Mission
+}
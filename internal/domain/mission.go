@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// Mission is a set of targets for one cat. Redacted is set when the notes
+// of the targets were withheld because the caller is not cleared for the
+// mission's classification.
+type Mission struct {
+	ID             int64          `json:"id"`
+	CatID          *int64         `json:"cat_id,omitempty"`
+	Complete       bool           `json:"complete"`
+	AssignedAt     *time.Time     `json:"assigned_at,omitempty"`
+	CompletedAt    *time.Time     `json:"completed_at,omitempty"`
+	StartsAt       *time.Time     `json:"starts_at,omitempty"`
+	DueAt          *time.Time     `json:"due_at,omitempty"`
+	Overdue        bool           `json:"overdue"`
+	Priority       Priority       `json:"priority"`
+	Classification Classification `json:"classification"`
+	Redacted       bool           `json:"redacted,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	Targets        []Target       `json:"targets"`
+}
+
+// Redact withholds the notes and locations of the mission's targets.
+func (m *Mission) Redact() {
+	for i := range m.Targets {
+		m.Targets[i].Redact()
+	}
+	m.Redacted = true
+}
+
+// IsOverdue reports whether an incomplete mission is past its due time.
+func (m *Mission) IsOverdue(now time.Time) bool {
+	return !m.Complete && m.DueAt != nil && m.DueAt.Before(now)
+}
+
+// OverdueMission is a mission found past its due time by the scheduler.
+type OverdueMission struct {
+	MissionID int64     `json:"mission_id"`
+	CatID     *int64    `json:"cat_id,omitempty"`
+	DueAt     time.Time `json:"due_at"`
+}
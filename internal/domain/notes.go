@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// NoteRevisionKind tells what a note revision recorded.
+type NoteRevisionKind string
+
+const (
+	// NoteRevisionNotes replaced the notes of a target; its text is the
+	// full notes after the change.
+	NoteRevisionNotes NoteRevisionKind = "notes"
+	// NoteRevisionObservation appended an observation to a target without
+	// changing its notes; its text is the observation alone.
+	NoteRevisionObservation NoteRevisionKind = "observation"
+)
+
+// NoteRevision is one entry in the history of a target's notes. Revisions
+// are numbered from 1 per target.
+type NoteRevision struct {
+	Revision  int              `json:"revision"`
+	Kind      NoteRevisionKind `json:"kind"`
+	Author    string           `json:"author,omitempty"`
+	Text      string           `json:"text"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// DiffOp tells how a line changed between two revisions.
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffDelete DiffOp = "delete"
+	DiffInsert DiffOp = "insert"
+)
+
+// DiffLine is a line of a NoteDiff.
+type DiffLine struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// NoteDiff is the line by line difference between the notes of two
+// revisions.
+type NoteDiff struct {
+	From  int        `json:"from"`
+	To    int        `json:"to"`
+	Lines []DiffLine `json:"lines"`
+}
@@ -0,0 +1,42 @@
+package domain
+
+// SearchHitType tells what a search hit refers to.
+type SearchHitType string
+
+const (
+	SearchHitCat     SearchHitType = "cat"
+	SearchHitMission SearchHitType = "mission"
+	SearchHitTarget  SearchHitType = "target"
+)
+
+// SearchHit is a cat, mission or target matching a search, best matches
+// first. Snippet is an excerpt of the matching text with the matched terms
+// wrapped in <mark> tags. Missions have no name and match by the name of
+// their cat and the names and countries of their targets. MissionID is the
+// mission of a target. Redacted is set on targets of missions the caller is
+// not cleared for, which only match by name and country.
+type SearchHit struct {
+	Type      SearchHitType `json:"type"`
+	ID        int64         `json:"id"`
+	MissionID *int64        `json:"mission_id,omitempty"`
+	Name      string        `json:"name,omitempty"`
+	Snippet   string        `json:"snippet"`
+	Redacted  bool          `json:"redacted,omitempty"`
+}
+
+// SearchTerm is a word or quoted phrase of a search, all of which must
+// match. Prefix matches words starting with Text. Country is the alpha-2
+// code of the country Text names, if any, so targets in that country match
+// too.
+type SearchTerm struct {
+	Text    string
+	Prefix  bool
+	Country string
+}
+
+// SearchQuery is a parsed search. The notes of targets are only searched
+// in missions with one of Classifications.
+type SearchQuery struct {
+	Terms           []SearchTerm
+	Classifications []Classification
+}
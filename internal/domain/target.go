@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+// Target is a target of a mission. Country holds an ISO 3166-1 alpha-2 code
+// and CountryName its display name, which is empty for countries recorded
+// before they were validated and not normalized since. Latitude and
+// Longitude are WGS 84 degrees, set together or not at all. Redacted is set
+// when the notes and location were withheld because the caller is not
+// cleared for the mission's classification.
+type Target struct {
+	ID          int64      `json:"id,omitempty"`
+	MissionID   int64      `json:"mission_id,omitempty"`
+	Name        string     `json:"name" validate:"required,min=1,max=100"`
+	Country     string     `json:"country" validate:"required,min=1,max=100"`
+	CountryName string     `json:"country_name,omitempty"`
+	Notes       string     `json:"notes" validate:"max=500"`
+	Latitude    *float64   `json:"latitude,omitempty" validate:"omitnil,min=-90,max=90"`
+	Longitude   *float64   `json:"longitude,omitempty" validate:"omitnil,min=-180,max=180"`
+	Address     string     `json:"address,omitempty" validate:"max=200"`
+	Complete    bool       `json:"complete"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	Overdue     bool       `json:"overdue"`
+	Redacted    bool       `json:"redacted,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// IsOverdue reports whether an incomplete target is past its deadline.
+func (t *Target) IsOverdue(now time.Time) bool {
+	return !t.Complete && t.DueAt != nil && t.DueAt.Before(now)
+}
+
+// Redact withholds the notes and location of the target.
+func (t *Target) Redact() {
+	t.Notes = ""
+	t.Address = ""
+	t.Latitude = nil
+	t.Longitude = nil
+	t.Redacted = true
+}
+
+// TargetUpdate lists the changes to the details of a target. Nil fields are
+// left unchanged.
+type TargetUpdate struct {
+	Name      *string
+	Country   *string
+	Address   *string
+	Latitude  *float64
+	Longitude *float64
+}
+
+// NearbyTarget is a target found by a proximity search, with the cat
+// assigned to its mission and its distance from the searched point.
+type NearbyTarget struct {
+	Target     Target  `json:"target"`
+	CatID      *int64  `json:"cat_id,omitempty"`
+	DistanceKM float64 `json:"distance_km"`
+}
+
+// TargetArea selects the targets with a location inside a latitude and
+// longitude range, on missions with one of Classifications. MinLon is
+// greater than MaxLon for a range crossing the antimeridian.
+type TargetArea struct {
+	MinLat, MaxLat  float64
+	MinLon, MaxLon  float64
+	Classifications []Classification
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// MissionTemplate is a named set of targets that missions can be created
+// from, with the priority and classification new missions get. The notes
+// of its targets are placeholders copied into every new mission. Redacted is
+// set when the notes were withheld because the caller is not cleared for
+// the template's classification.
+type MissionTemplate struct {
+	ID             int64            `json:"id"`
+	Name           string           `json:"name" validate:"required,min=1,max=100"`
+	Priority       Priority         `json:"priority"`
+	Classification Classification   `json:"classification"`
+	CreatedBy      string           `json:"created_by,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	Targets        []TemplateTarget `json:"targets" validate:"dive"`
+	Redacted       bool             `json:"redacted,omitempty"`
+}
+
+// Redact withholds the notes of the template's targets.
+func (t *MissionTemplate) Redact() {
+	for i := range t.Targets {
+		t.Targets[i].Notes = ""
+	}
+	t.Redacted = true
+}
+
+// TemplateTarget is a target of a mission template. Country holds an ISO
+// 3166-1 alpha-2 code like the country of a target.
+type TemplateTarget struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Country     string `json:"country" validate:"required,min=1,max=100"`
+	CountryName string `json:"country_name,omitempty"`
+	Notes       string `json:"notes" validate:"max=500"`
+}
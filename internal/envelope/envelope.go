@@ -0,0 +1,141 @@
+// Package envelope encrypts small values with envelope encryption: every
+// value is sealed with its own random data key, and the data key is sealed
+// with a long-lived key-encryption key (KEK). Rotating the KEK only needs
+// the data keys to be sealed again, and a leaked row reveals nothing
+// without the KEK.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the size in bytes of key-encryption keys and data keys, which
+// selects AES-256.
+const KeySize = 32
+
+var ErrUnknownKey = errors.New("unknown key-encryption key")
+
+// Sealed is an encrypted value. DataKey is the data key sealed with the KEK
+// named KeyID; Ciphertext is the value sealed with the data key. Both carry
+// their nonce as a prefix.
+type Sealed struct {
+	KeyID      string
+	DataKey    []byte
+	Ciphertext []byte
+}
+
+// Keyring holds the key-encryption keys. New values are sealed with the
+// active key; values sealed with any key in the ring can be opened.
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewKeyring builds a keyring from keys, which maps key IDs to KeySize
+// bytes of key material, sealing new values with the key named active.
+func NewKeyring(active string, keys map[string][]byte) (*Keyring, error) {
+	const op = "envelope.NewKeyring"
+
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("%s: active key %q is not configured", op, active)
+	}
+
+	k := &Keyring{active: active, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %q: %w", op, id, err)
+		}
+		k.keys[id] = aead
+	}
+	return k, nil
+}
+
+// ActiveKeyID is the ID of the key new values are sealed with.
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// Seal encrypts plaintext under a fresh data key sealed with the active KEK.
+func (k *Keyring) Seal(plaintext []byte) (Sealed, error) {
+	const op = "envelope.Keyring.Seal"
+
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return Sealed{}, fmt.Errorf("%s: generate data key: %w", op, err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return Sealed{}, fmt.Errorf("%s: %w", op, err)
+	}
+	ciphertext, err := seal(aead, plaintext, nil)
+	if err != nil {
+		return Sealed{}, fmt.Errorf("%s: seal value: %w", op, err)
+	}
+
+	// The key ID is authenticated with the data key, so a sealed data key
+	// cannot be passed off as belonging to another KEK.
+	sealedKey, err := seal(k.keys[k.active], dataKey, []byte(k.active))
+	if err != nil {
+		return Sealed{}, fmt.Errorf("%s: seal data key: %w", op, err)
+	}
+
+	return Sealed{KeyID: k.active, DataKey: sealedKey, Ciphertext: ciphertext}, nil
+}
+
+// Open decrypts a value sealed by Seal with any key in the ring.
+func (k *Keyring) Open(s Sealed) ([]byte, error) {
+	const op = "envelope.Keyring.Open"
+
+	kek, ok := k.keys[s.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w %q", op, ErrUnknownKey, s.KeyID)
+	}
+
+	dataKey, err := open(kek, s.DataKey, []byte(s.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: open data key: %w", op, err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	plaintext, err := open(aead, s.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: open value: %w", op, err)
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
@@ -0,0 +1,183 @@
+package envelope
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func newKey(t *testing.T) []byte {
+	t.Helper()
+
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	ring, err := NewKeyring("k1", map[string][]byte{"k1": newKey(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, plaintext := range []string{"", "meet at the fish market", string(bytes.Repeat([]byte("x"), 4096))} {
+		sealed, err := ring.Seal([]byte(plaintext))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sealed.KeyID != "k1" {
+			t.Errorf("sealed with key %q, want k1", sealed.KeyID)
+		}
+		if len(plaintext) > 0 && bytes.Contains(sealed.Ciphertext, []byte(plaintext)) {
+			t.Errorf("ciphertext contains the plaintext")
+		}
+
+		opened, err := ring.Open(sealed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(opened) != plaintext {
+			t.Errorf("opened %q, want %q", opened, plaintext)
+		}
+	}
+}
+
+func TestSealUsesFreshDataKeys(t *testing.T) {
+	ring, err := NewKeyring("k1", map[string][]byte{"k1": newKey(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := ring.Seal([]byte("same"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ring.Seal([]byte("same"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(a.DataKey, b.DataKey) || bytes.Equal(a.Ciphertext, b.Ciphertext) {
+		t.Error("sealing the same value twice gave the same output")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	oldMaterial, newMaterial := newKey(t), newKey(t)
+
+	before, err := NewKeyring("old", map[string][]byte{"old": oldMaterial})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := before.Seal([]byte("notes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// While rotating, both keys are configured and the new one is active.
+	during, err := NewKeyring("new", map[string][]byte{"old": oldMaterial, "new": newMaterial})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if during.ActiveKeyID() != "new" {
+		t.Fatalf("active key %q, want new", during.ActiveKeyID())
+	}
+	opened, err := during.Open(sealed)
+	if err != nil {
+		t.Fatalf("open value sealed with the old key: %v", err)
+	}
+	resealed, err := during.Seal(opened)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resealed.KeyID != "new" {
+		t.Errorf("resealed with key %q, want new", resealed.KeyID)
+	}
+
+	// Once the old key is removed, only resealed values can be opened.
+	after, err := NewKeyring("new", map[string][]byte{"new": newMaterial})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := after.Open(sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("open with the old key removed: got %v, want %v", err, ErrUnknownKey)
+	}
+	opened, err = after.Open(resealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(opened) != "notes" {
+		t.Errorf("opened %q, want %q", opened, "notes")
+	}
+}
+
+func TestOpenFailsWithWrongKey(t *testing.T) {
+	ring, err := NewKeyring("k1", map[string][]byte{"k1": newKey(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := ring.Seal([]byte("notes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Same key ID, different key material.
+	other, err := NewKeyring("k1", map[string][]byte{"k1": newKey(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Open(sealed); err == nil {
+		t.Error("opened a value with the wrong key")
+	}
+
+	// A data key relabelled as sealed by another KEK is rejected even when
+	// that KEK is the right key material.
+	key := newKey(t)
+	both, err := NewKeyring("a", map[string][]byte{"a": key, "b": key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err = both.Seal([]byte("notes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed.KeyID = "b"
+	if _, err := both.Open(sealed); err == nil {
+		t.Error("opened a value whose key ID was changed")
+	}
+}
+
+func TestOpenFailsOnTampering(t *testing.T) {
+	ring, err := NewKeyring("k1", map[string][]byte{"k1": newKey(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := ring.Seal([]byte("notes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := sealed
+	tampered.Ciphertext = bytes.Clone(sealed.Ciphertext)
+	tampered.Ciphertext[len(tampered.Ciphertext)-1] ^= 1
+	if _, err := ring.Open(tampered); err == nil {
+		t.Error("opened a tampered ciphertext")
+	}
+
+	tampered = sealed
+	tampered.Ciphertext = tampered.Ciphertext[:3]
+	if _, err := ring.Open(tampered); err == nil {
+		t.Error("opened a truncated ciphertext")
+	}
+}
+
+func TestNewKeyringRejectsInvalidKeys(t *testing.T) {
+	if _, err := NewKeyring("missing", map[string][]byte{"k1": newKey(t)}); err == nil {
+		t.Error("accepted an active key that is not configured")
+	}
+	if _, err := NewKeyring("k1", map[string][]byte{"k1": make([]byte, 16)}); err == nil {
+		t.Error("accepted a key of the wrong size")
+	}
+}
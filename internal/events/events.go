@@ -0,0 +1,58 @@
+// Package events carries notifications about missions to whoever needs to
+// react to them, such as the handlers running the cats.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Type names an event.
+type Type string
+
+const (
+	// MissionOverdue is emitted once when an incomplete mission passes its
+	// due time.
+	MissionOverdue Type = "mission.overdue"
+)
+
+// Event is something that happened to a mission.
+type Event struct {
+	Type       Type       `json:"type"`
+	MissionID  int64      `json:"mission_id"`
+	CatID      *int64     `json:"cat_id,omitempty"`
+	DueAt      *time.Time `json:"due_at,omitempty"`
+	OccurredAt time.Time  `json:"occurred_at"`
+}
+
+// Publisher delivers events.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// LogPublisher writes every event to a logger.
+type LogPublisher struct {
+	logger *slog.Logger
+}
+
+func NewLogPublisher(logger *slog.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger.With(slog.String("component", "events"))}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event Event) error {
+	attrs := []slog.Attr{
+		slog.String("type", string(event.Type)),
+		slog.Int64("mission_id", event.MissionID),
+		slog.Time("occurred_at", event.OccurredAt),
+	}
+	if event.CatID != nil {
+		attrs = append(attrs, slog.Int64("cat_id", *event.CatID))
+	}
+	if event.DueAt != nil {
+		attrs = append(attrs, slog.Time("due_at", *event.DueAt))
+	}
+
+	p.logger.LogAttrs(ctx, slog.LevelWarn, "mission event", attrs...)
+	return nil
+}
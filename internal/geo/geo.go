@@ -0,0 +1,91 @@
+// Package geo computes distances between points on the Earth's surface and
+// the bounding boxes used to search around a point.
+package geo
+
+import "math"
+
+// EarthRadiusKM is the mean radius of the Earth.
+const EarthRadiusKM = 6371.0088
+
+// MaxDistanceKM is half the Earth's circumference, the largest distance
+// between two points.
+const MaxDistanceKM = math.Pi * EarthRadiusKM
+
+// Point is a WGS 84 position in degrees.
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Valid reports whether p lies within the range of latitudes and
+// longitudes.
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+// DistanceKM returns the great-circle distance between a and b using the
+// haversine formula.
+func DistanceKM(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLon := radians(b.Lon - a.Lon)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadiusKM * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Box is a latitude and longitude range. When the box crosses the
+// antimeridian MinLon is greater than MaxLon, and a longitude matches when
+// it is at least MinLon or at most MaxLon.
+type Box struct {
+	MinLat, MaxLat float64
+	MinLon, MaxLon float64
+}
+
+// CrossesAntimeridian reports whether the longitude range of b wraps
+// around from 180 to -180.
+func (b Box) CrossesAntimeridian() bool {
+	return b.MinLon > b.MaxLon
+}
+
+// BoundingBox returns a box containing every point within radiusKM of
+// center. It is only a prefilter: corners of the box lie further away than
+// radiusKM. Near the poles the box spans all longitudes.
+func BoundingBox(center Point, radiusKM float64) Box {
+	dLat := degrees(radiusKM / EarthRadiusKM)
+	box := Box{
+		MinLat: math.Max(center.Lat-dLat, -90),
+		MaxLat: math.Min(center.Lat+dLat, 90),
+		MinLon: -180,
+		MaxLon: 180,
+	}
+	if box.MinLat == -90 || box.MaxLat == 90 {
+		return box
+	}
+
+	// The widest longitude span is reached at the latitude where the circle
+	// touches its meridians, not at the center.
+	ratio := math.Sin(radiusKM/EarthRadiusKM) / math.Cos(radians(center.Lat))
+	if ratio >= 1 {
+		return box
+	}
+	dLon := degrees(math.Asin(ratio))
+
+	box.MinLon = center.Lon - dLon
+	box.MaxLon = center.Lon + dLon
+	if box.MinLon < -180 {
+		box.MinLon += 360
+	}
+	if box.MaxLon > 180 {
+		box.MaxLon -= 360
+	}
+	return box
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
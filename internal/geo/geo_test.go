@@ -0,0 +1,121 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// oneDegreeKM is the length of one degree of a great circle.
+const oneDegreeKM = 2 * math.Pi * EarthRadiusKM / 360
+
+func TestDistanceKM(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		{"same point", Point{50.45, 30.52}, Point{50.45, 30.52}, 0},
+		{"one degree along the equator", Point{0, 0}, Point{0, 1}, oneDegreeKM},
+		{"one degree along a meridian", Point{10, 20}, Point{11, 20}, oneDegreeKM},
+		{"across the antimeridian", Point{0, 179.5}, Point{0, -179.5}, oneDegreeKM},
+		{"pole to pole", Point{90, 0}, Point{-90, 0}, MaxDistanceKM},
+		{"antipodes", Point{0, 0}, Point{0, 180}, MaxDistanceKM},
+		{"meridians meet at the pole", Point{90, 0}, Point{90, 120}, 0},
+		{"London to Paris", Point{51.5074, -0.1278}, Point{48.8566, 2.3522}, 343.56},
+		{"Kyiv to Warsaw", Point{50.4501, 30.5234}, Point{52.2297, 21.0122}, 689.09},
+		{"New York to London", Point{40.7128, -74.0060}, Point{51.5074, -0.1278}, 5570.23},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistanceKM(tt.a, tt.b)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("DistanceKM(%v, %v) = %.3f, want %.3f", tt.a, tt.b, got, tt.want)
+			}
+			if back := DistanceKM(tt.b, tt.a); math.Abs(back-got) > 1e-9 {
+				t.Errorf("DistanceKM is not symmetric: %.6f and %.6f", got, back)
+			}
+		})
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	tests := []struct {
+		name   string
+		center Point
+		radius float64
+		want   Box
+	}{
+		{"equator", Point{0, 0}, oneDegreeKM, Box{-1, 1, -1, 1}},
+		{"longitudes widen with latitude", Point{60, 0}, oneDegreeKM, Box{59, 61, -2.0003, 2.0003}},
+		{"east of the antimeridian", Point{0, 179.5}, oneDegreeKM, Box{-1, 1, 178.5, -179.5}},
+		{"west of the antimeridian", Point{0, -179.5}, oneDegreeKM, Box{-1, 1, 179.5, -178.5}},
+		{"reaching the north pole", Point{89.5, 10}, oneDegreeKM, Box{88.5, 90, -180, 180}},
+		{"reaching the south pole", Point{-89.5, -170}, oneDegreeKM, Box{-90, -88.5, -180, 180}},
+		{"at the pole", Point{90, 0}, 1, Box{89.991, 90, -180, 180}},
+		{"whole earth", Point{10, 10}, MaxDistanceKM, Box{-90, 90, -180, 180}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BoundingBox(tt.center, tt.radius)
+			if !boxNear(got, tt.want, 0.001) {
+				t.Errorf("BoundingBox(%v, %.3f) = %+v, want %+v", tt.center, tt.radius, got, tt.want)
+			}
+			if got.CrossesAntimeridian() != (tt.want.MinLon > tt.want.MaxLon) {
+				t.Errorf("CrossesAntimeridian() = %v", got.CrossesAntimeridian())
+			}
+		})
+	}
+}
+
+// TestBoundingBoxContainsCircle walks the circle around centers near the
+// poles and the antimeridian and checks that the box never cuts it off.
+func TestBoundingBoxContainsCircle(t *testing.T) {
+	centers := []Point{
+		{0, 0}, {45, 90}, {-60, -45},
+		{89, 0}, {-89, 179}, {85, -120},
+		{0, 179.9}, {0, -179.9}, {70, 180}, {-70, -180},
+	}
+
+	for _, center := range centers {
+		for _, radius := range []float64{1, 50, 500, 2000} {
+			box := BoundingBox(center, radius)
+			for bearing := 0.0; bearing < 360; bearing += 5 {
+				p := destination(center, bearing, radius)
+				if !inBox(box, p) {
+					t.Errorf("BoundingBox(%v, %.0f) = %+v misses %v at bearing %.0f", center, radius, box, p, bearing)
+				}
+			}
+		}
+	}
+}
+
+// destination returns the point distanceKM from start along bearing, in
+// degrees clockwise from north.
+func destination(start Point, bearing, distanceKM float64) Point {
+	lat1, lon1 := radians(start.Lat), radians(start.Lon)
+	theta, delta := radians(bearing), distanceKM/EarthRadiusKM
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+
+	lon := math.Mod(degrees(lon2)+540, 360) - 180
+	return Point{Lat: degrees(lat2), Lon: lon}
+}
+
+func inBox(b Box, p Point) bool {
+	const eps = 1e-9
+	if p.Lat < b.MinLat-eps || p.Lat > b.MaxLat+eps {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return p.Lon >= b.MinLon-eps || p.Lon <= b.MaxLon+eps
+	}
+	return p.Lon >= b.MinLon-eps && p.Lon <= b.MaxLon+eps
+}
+
+func boxNear(a, b Box, tolerance float64) bool {
+	return math.Abs(a.MinLat-b.MinLat) <= tolerance && math.Abs(a.MaxLat-b.MaxLat) <= tolerance &&
+		math.Abs(a.MinLon-b.MinLon) <= tolerance && math.Abs(a.MaxLon-b.MaxLon) <= tolerance
+}
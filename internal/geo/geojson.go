@@ -0,0 +1,43 @@
+package geo
+
+// MediaTypeGeoJSON is the media type of GeoJSON documents (RFC 7946).
+const MediaTypeGeoJSON = "application/geo+json"
+
+// FeatureCollection is a GeoJSON feature collection.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON feature. Geometry is nil, encoded as null, for a
+// feature with no known location.
+type Feature struct {
+	Type       string         `json:"type"`
+	ID         any            `json:"id,omitempty"`
+	Geometry   *Geometry      `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Geometry is a GeoJSON point. Coordinates are longitude first, as
+// GeoJSON requires.
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+func NewFeatureCollection(features []Feature) FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// NewFeature returns a feature located at p, or with no geometry when p is
+// nil.
+func NewFeature(id any, p *Point, properties map[string]any) Feature {
+	f := Feature{Type: "Feature", ID: id, Properties: properties}
+	if p != nil {
+		f.Geometry = &Geometry{Type: "Point", Coordinates: []float64{p.Lon, p.Lat}}
+	}
+	return f
+}
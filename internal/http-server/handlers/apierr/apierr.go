@@ -0,0 +1,132 @@
+// Package apierr translates errors returned by the service layer into HTTP
+// responses, so every handler reports the same failure the same way.
+package apierr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/rules"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+var notFound = []error{
+	domain.ErrCatNotFound,
+	domain.ErrMissionNotFound,
+	domain.ErrTargetNotFound,
+	domain.ErrBreedNotFound,
+	domain.ErrRevisionNotFound,
+	domain.ErrAttachmentNotFound,
+	domain.ErrTemplateNotFound,
+}
+
+var conflict = []error{
+	domain.ErrMissionComplete,
+	domain.ErrTargetComplete,
+	domain.ErrMissionAssigned,
+	domain.ErrIncompleteTargets,
+	domain.ErrNoCandidates,
+	domain.ErrTemplateExists,
+	domain.ErrDeclassification,
+}
+
+var forbidden = []error{
+	domain.ErrInsufficientClearance,
+}
+
+var badRequest = []error{
+	domain.ErrInvalidBreed,
+}
+
+var tooLarge = []error{
+	domain.ErrAttachmentTooLarge,
+}
+
+var unsupportedMediaType = []error{
+	domain.ErrUnsupportedMediaType,
+}
+
+type breedErrorResponse struct {
+	Error       string   `json:"error"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// Write responds with the status code that matches err. Errors that are not
+// caused by the request are reported as 500 with the generic message msg so
+// storage details do not leak to clients.
+func Write(w http.ResponseWriter, err error, msg string) {
+	status, body := Response(err, msg)
+	utils.WriteJSON(w, status, body)
+}
+
+// Response returns the status code and body Write would respond with, for
+// callers that report several errors in one response.
+func Response(err error, msg string) (int, any) {
+	var violation *rules.Violation
+	if errors.As(err, &violation) {
+		return http.StatusUnprocessableEntity, violation
+	}
+
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest, errorBody(validationErr)
+	}
+
+	var breedErr *domain.BreedError
+	if errors.As(err, &breedErr) {
+		return http.StatusBadRequest, breedErrorResponse{
+			Error:       breedErr.Error(),
+			Suggestions: breedErr.Suggestions,
+		}
+	}
+
+	if target := match(err, badRequest); target != nil {
+		return http.StatusBadRequest, errorBody(target)
+	}
+
+	if target := match(err, tooLarge); target != nil {
+		return http.StatusRequestEntityTooLarge, errorBody(target)
+	}
+
+	if target := match(err, unsupportedMediaType); target != nil {
+		return http.StatusUnsupportedMediaType, errorBody(target)
+	}
+
+	if target := match(err, forbidden); target != nil {
+		return http.StatusForbidden, errorBody(target)
+	}
+
+	if target := match(err, notFound); target != nil {
+		return http.StatusNotFound, errorBody(target)
+	}
+
+	if target := match(err, conflict); target != nil {
+		return http.StatusConflict, errorBody(target)
+	}
+
+	if errors.Is(err, domain.ErrBatchAborted) {
+		return http.StatusFailedDependency, errorBody(domain.ErrBatchAborted)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, errorBody(errors.New("request timed out"))
+	}
+
+	return http.StatusInternalServerError, errorBody(errors.New(msg))
+}
+
+// errorBody is the body utils.WriteError responds with.
+func errorBody(err error) map[string]string {
+	return map[string]string{"error": err.Error()}
+}
+
+func match(err error, targets []error) error {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type EntryLister interface {
+	GetEntries(ctx context.Context, filter domain.AuditFilter, limit, offset int) ([]domain.AuditEntry, int, error)
+}
+
+type EntriesResponse struct {
+	Entries []domain.AuditEntry `json:"entries"`
+	Total   int                 `json:"total"`
+	Limit   int                 `json:"limit"`
+	Offset  int                 `json:"offset"`
+}
+
+// GetAllHandler lists audit log entries, newest first, optionally filtered
+// with action, mission_id and target_id, and paginated with ?limit= and
+// ?offset=.
+func GetAllHandler(logger *slog.Logger, entryLister EntryLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.audit.list"
+		logger = logger.With(slog.String("op", op))
+
+		filter := domain.AuditFilter{Action: domain.AuditAction(r.URL.Query().Get("action"))}
+		var err error
+		if filter.MissionID, err = utils.ParseIDParam(r, "mission_id"); err == nil {
+			filter.TargetID, err = utils.ParseIDParam(r, "target_id")
+		}
+		if err != nil {
+			logger.Error("invalid filter", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		limit, offset, err := utils.ParsePagination(r)
+		if err != nil {
+			logger.Error("invalid pagination parameters", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		entries, total, err := entryLister.GetEntries(r.Context(), filter, limit, offset)
+		if err != nil {
+			logger.Error("failed to list audit entries", slog.Any("error", err))
+			apierr.Write(w, err, "failed to list audit entries")
+			return
+		}
+		if entries == nil {
+			entries = []domain.AuditEntry{}
+		}
+
+		logger.Info("audit entries listed successfully", slog.Int("count", len(entries)))
+		utils.WriteJSON(w, http.StatusOK, EntriesResponse{
+			Entries: entries,
+			Total:   total,
+			Limit:   limit,
+			Offset:  offset,
+		})
+	}
+}
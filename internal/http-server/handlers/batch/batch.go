@@ -0,0 +1,57 @@
+// Package batch holds what the batch endpoints have in common: reporting
+// the outcome of every item of a batch in one response.
+package batch
+
+import (
+	"net/http"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+// Result is the outcome of one item. Status and Error are what the request
+// for the item alone would have responded with.
+type Result struct {
+	Index  int   `json:"index"`
+	Status int   `json:"status"`
+	ID     int64 `json:"id,omitempty"`
+	Error  any   `json:"error,omitempty"`
+}
+
+type Response struct {
+	Mode      domain.BatchMode `json:"mode"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []Result         `json:"results"`
+}
+
+// Write responds with the results of a batch. When every item succeeded the
+// status is ok, the status of a successful item. Otherwise an atomic batch
+// responds with the status of its first failing item and a partial one
+// with 207 Multi-Status. msg is reported for items that failed for reasons
+// not caused by the request.
+func Write(w http.ResponseWriter, mode domain.BatchMode, results []domain.BatchResult, ok int, msg string) {
+	resp := Response{Mode: mode, Results: make([]Result, len(results))}
+	status := ok
+	for i, r := range results {
+		if r.Err == nil {
+			resp.Results[i] = Result{Index: r.Index, Status: ok, ID: r.ID}
+			resp.Succeeded++
+			continue
+		}
+
+		itemStatus, body := apierr.Response(r.Err, msg)
+		resp.Results[i] = Result{Index: r.Index, Status: itemStatus, ID: r.ID, Error: body}
+		resp.Failed++
+
+		switch {
+		case mode == domain.BatchPartial:
+			status = http.StatusMultiStatus
+		case status == ok && itemStatus != http.StatusFailedDependency:
+			status = itemStatus
+		}
+	}
+
+	utils.WriteJSON(w, status, resp)
+}
@@ -0,0 +1,39 @@
+package breeds
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type BreedSearcher interface {
+	SearchBreeds(query string) []domain.Breed
+}
+
+type GetAllResponse struct {
+	Breeds []domain.Breed `json:"breeds"`
+}
+
+// GetAllHandler lists the known breeds. With ?q= only breeds matching the
+// query by prefix or by a close spelling are returned, best match first.
+func GetAllHandler(logger *slog.Logger, breedSearcher BreedSearcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.breeds.get_all"
+
+		logger = logger.With(slog.String("op", op))
+
+		query := r.URL.Query().Get("q")
+		breeds := breedSearcher.SearchBreeds(query)
+		if breeds == nil {
+			breeds = []domain.Breed{}
+		}
+
+		logger.Info("retrieved breeds successfully", slog.String("q", query), slog.Int("count", len(breeds)))
+
+		utils.WriteJSON(w, http.StatusOK, GetAllResponse{
+			Breeds: breeds,
+		})
+	}
+}
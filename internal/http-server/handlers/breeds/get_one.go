@@ -0,0 +1,39 @@
+package breeds
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type BreedGetter interface {
+	GetBreed(id string) (*domain.Breed, error)
+}
+
+type GetOneResponse struct {
+	Breed *domain.Breed `json:"breed"`
+}
+
+func GetOneHandler(logger *slog.Logger, breedGetter BreedGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.breeds.get_one"
+
+		logger = logger.With(slog.String("op", op))
+
+		id := chi.URLParam(r, "id")
+
+		breed, err := breedGetter.GetBreed(id)
+		if err != nil {
+			logger.Error("failed to get breed", slog.String("id", id), slog.Any("error", err))
+			apierr.Write(w, err, "failed to get breed")
+			return
+		}
+
+		logger.Info("retrieved breed successfully", slog.String("id", id))
+		utils.WriteJSON(w, http.StatusOK, GetOneResponse{Breed: breed})
+	}
+}
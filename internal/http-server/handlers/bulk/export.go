@@ -0,0 +1,98 @@
+// Package bulk exports and imports cats and missions in bulk.
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/transfer"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+// StatusTrailer is the trailer an export ends with: "complete" when every
+// requested entity was written and "failed" otherwise. A response without
+// it was cut off.
+const StatusTrailer = "X-Export-Status"
+
+// failedMessage ends an export that failed part way. The cause is only
+// logged, as it may reveal storage details.
+const failedMessage = "the export failed part way and is incomplete"
+
+type Exporter interface {
+	ExportCats(ctx context.Context, fn func(domain.SpyCat) error) error
+	ExportMissions(ctx context.Context, fn func(domain.Mission) error) error
+}
+
+// ExportHandler streams the entities listed in the entities query
+// parameter, all of them by default, in the format given by the format
+// query parameter. Once streaming has begun the status can no longer
+// change, so a failure part way ends the document with an error record and
+// sets StatusTrailer to "failed".
+func ExportHandler(logger *slog.Logger, exporter Exporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.bulk.export"
+		logger = logger.With(slog.String("op", op))
+
+		requested, err := utils.ParseListParam(r, "entities", transfer.ParseEntity)
+		if err != nil {
+			logger.Error("invalid entities", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		// Entities are always exported in the same order, each once.
+		var entities []transfer.Entity
+		for _, entity := range transfer.Entities {
+			if len(requested) == 0 || slices.Contains(requested, entity) {
+				entities = append(entities, entity)
+			}
+		}
+
+		format, err := transfer.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			logger.Error("invalid format", slog.Any("error", err))
+			apierr.Write(w, err, "failed to export")
+			return
+		}
+
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="spy-cat-export.%s"`, format))
+		w.Header().Set("Trailer", StatusTrailer)
+
+		enc := transfer.NewEncoder(w, format, entities)
+		for _, entity := range entities {
+			if err = enc.Start(entity); err != nil {
+				break
+			}
+			switch entity {
+			case transfer.Cats:
+				err = exporter.ExportCats(r.Context(), enc.WriteCat)
+			case transfer.Missions:
+				err = exporter.ExportMissions(r.Context(), enc.WriteMission)
+			}
+			if err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = enc.Close()
+		}
+		if err != nil {
+			logger.Error("export failed", slog.Any("error", err))
+			// Fails too when the client has gone away, and then there is
+			// no one left to tell.
+			if err := enc.Fail(failedMessage); err != nil {
+				logger.Error("failed to mark export as incomplete", slog.Any("error", err))
+			}
+			w.Header().Set(StatusTrailer, "failed")
+			return
+		}
+		w.Header().Set(StatusTrailer, "complete")
+
+		logger.Info("export completed", slog.String("format", string(format)), slog.Any("entities", entities))
+	}
+}
@@ -0,0 +1,58 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/transfer"
+)
+
+// failingExporter exports one cat and then fails.
+type failingExporter struct{}
+
+func (failingExporter) ExportCats(_ context.Context, fn func(domain.SpyCat) error) error {
+	if err := fn(domain.SpyCat{ID: 1, Name: "Tom", Breed: "Bengal", Salary: 100}); err != nil {
+		return err
+	}
+	return errors.New("database is locked")
+}
+
+func (failingExporter) ExportMissions(context.Context, func(domain.Mission) error) error {
+	return nil
+}
+
+func TestExportFailurePartWay(t *testing.T) {
+	handler := ExportHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), failingExporter{})
+
+	for _, format := range []transfer.Format{transfer.JSON, transfer.NDJSON, transfer.CSV} {
+		t.Run(string(format), func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/export?format="+string(format), nil))
+
+			resp := rec.Result()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			if got := resp.Trailer.Get(StatusTrailer); got != "failed" {
+				t.Errorf("%s trailer = %q, want failed", StatusTrailer, got)
+			}
+
+			body := rec.Body.String()
+			if strings.Contains(body, "database is locked") {
+				t.Errorf("body reveals the cause of the failure:\n%s", body)
+			}
+			_, err := transfer.Decode(strings.NewReader(body), format)
+			var invalid *domain.ValidationError
+			if !errors.As(err, &invalid) || !strings.Contains(err.Error(), "the export is incomplete") {
+				t.Errorf("decoding the partial export: got %v, want it rejected as incomplete\n%s", err, body)
+			}
+		})
+	}
+}
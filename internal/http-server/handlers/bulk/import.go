@@ -0,0 +1,128 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/transfer"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+// MaxImportSize bounds the size of an import in bytes.
+const MaxImportSize = 10 << 20
+
+type Importer interface {
+	Import(ctx context.Context, records []domain.ImportRecord, dryRun bool) ([]domain.BatchResult, error)
+}
+
+// ImportResponse counts the cats and missions imported, or that a dry run
+// found could be imported, and lists the records that could not.
+type ImportResponse struct {
+	DryRun   bool          `json:"dry_run"`
+	Cats     int           `json:"cats"`
+	Missions int           `json:"missions"`
+	Errors   []RecordError `json:"errors"`
+}
+
+// RecordError reports a record that could not be imported. Line is where
+// the record starts in CSV and NDJSON imports, and Index its position among
+// the records of its type. Status and Error are what creating the cat or
+// mission alone would have responded with.
+type RecordError struct {
+	Line   int    `json:"line,omitempty"`
+	Type   string `json:"type"`
+	Index  int    `json:"index"`
+	Status int    `json:"status"`
+	Error  any    `json:"error"`
+}
+
+// ImportHandler imports the body in the format given by the format query
+// parameter. With dry_run=true nothing is imported and every record is
+// reported on; otherwise the import is rejected with 422 when any record
+// fails.
+func ImportHandler(logger *slog.Logger, importer Importer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.bulk.import"
+		logger = logger.With(slog.String("op", op))
+
+		format, err := transfer.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			logger.Error("invalid format", slog.Any("error", err))
+			apierr.Write(w, err, "failed to import")
+			return
+		}
+
+		dryRun, err := utils.ParseBoolParam(r, "dry_run")
+		if err != nil {
+			logger.Error("invalid dry_run", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		records, err := transfer.Decode(http.MaxBytesReader(w, r.Body, MaxImportSize), format)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			logger.Error("import too large", slog.Any("error", err))
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("an import may be at most %d bytes", MaxImportSize))
+			return
+		}
+		if err != nil {
+			logger.Error("failed to decode import", slog.Any("error", err))
+			apierr.Write(w, err, "failed to import")
+			return
+		}
+
+		resp := ImportResponse{DryRun: dryRun != nil && *dryRun, Errors: []RecordError{}}
+		results, err := importer.Import(r.Context(), records, resp.DryRun)
+		if err != nil {
+			logger.Error("failed to import", slog.Any("error", err))
+			apierr.Write(w, err, "failed to import")
+			return
+		}
+
+		for _, result := range results {
+			record := records[result.Index]
+			switch {
+			case result.Err == nil && record.Cat != nil:
+				resp.Cats++
+			case result.Err == nil:
+				resp.Missions++
+			case !errors.Is(result.Err, domain.ErrBatchAborted):
+				// Records rolled back because of others are left out,
+				// they did not fail themselves.
+				status, body := apierr.Response(result.Err, "failed to import record")
+				resp.Errors = append(resp.Errors, RecordError{
+					Line:   record.Line,
+					Type:   recordType(record),
+					Index:  record.Index,
+					Status: status,
+					Error:  body,
+				})
+			}
+		}
+
+		status := http.StatusCreated
+		switch {
+		case resp.DryRun:
+			status = http.StatusOK
+		case len(resp.Errors) > 0:
+			status = http.StatusUnprocessableEntity
+		}
+
+		logger.Info("import processed", slog.Bool("dry_run", resp.DryRun), slog.Int("cats", resp.Cats),
+			slog.Int("missions", resp.Missions), slog.Int("errors", len(resp.Errors)))
+		utils.WriteJSON(w, status, resp)
+	}
+}
+
+func recordType(r domain.ImportRecord) string {
+	if r.Cat != nil {
+		return "cat"
+	}
+	return "mission"
+}
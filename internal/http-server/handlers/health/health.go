@@ -0,0 +1,38 @@
+package health
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/illiakornyk/spy-cat/internal/breeds"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type BreedHealthReporter interface {
+	Health() breeds.Health
+}
+
+type Response struct {
+	Status string        `json:"status"`
+	Breeds breeds.Health `json:"breeds"`
+}
+
+// Handler reports the health of the service's dependencies. A degraded
+// breed source still serves requests, so it does not change the HTTP status.
+func Handler(logger *slog.Logger, breedHealth BreedHealthReporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.health"
+
+		logger = logger.With(slog.String("op", op))
+
+		breedStatus := breedHealth.Health()
+		if breedStatus.Status != "ok" {
+			logger.Warn("breed source degraded", slog.String("source", string(breedStatus.Source)))
+		}
+
+		utils.WriteJSON(w, http.StatusOK, Response{
+			Status: breedStatus.Status,
+			Breeds: breedStatus,
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package missions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/matching"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type AutoAssigner interface {
+	AutoAssign(ctx context.Context, missionID int64) (*matching.Candidate, error)
+}
+
+type AutoAssignResponse struct {
+	Assigned *matching.Candidate `json:"assigned"`
+}
+
+// AutoAssignHandler assigns the best ranked candidate to a mission.
+func AutoAssignHandler(logger *slog.Logger, autoAssigner AutoAssigner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.auto_assign"
+
+		logger = logger.With(slog.String("op", op))
+
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			logger.Error("invalid mission id", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid mission id"))
+			return
+		}
+
+		candidate, err := autoAssigner.AutoAssign(r.Context(), id)
+		if err != nil {
+			logger.Error("failed to auto-assign mission", slog.Any("error", err))
+			apierr.Write(w, err, "failed to auto-assign mission")
+			return
+		}
+
+		logger.Info("cat auto-assigned to mission successfully", slog.Int64("missionID", id), slog.Int64("catID", candidate.Cat.ID))
+
+		utils.WriteJSON(w, http.StatusOK, AutoAssignResponse{Assigned: candidate})
+	}
+}
@@ -0,0 +1,169 @@
+package missions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/batch"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+// BatchCreateRequest creates Items, all or none of them in the default
+// atomic mode, as many as succeed in partial mode.
+type BatchCreateRequest struct {
+	Mode  string          `json:"mode,omitempty"`
+	Items []CreateRequest `json:"items"`
+}
+
+// BatchUpdateRequest applies Items like BatchCreateRequest.
+type BatchUpdateRequest struct {
+	Mode  string            `json:"mode,omitempty"`
+	Items []BatchUpdateItem `json:"items"`
+}
+
+// BatchUpdateItem completes or reopens the mission with ID, or assigns a
+// cat to it.
+type BatchUpdateItem struct {
+	ID       int64  `json:"id"`
+	Complete *bool  `json:"complete,omitempty"`
+	CatID    *int64 `json:"cat_id,omitempty"`
+}
+
+type MissionBatchCreator interface {
+	CreateMissions(ctx context.Context, mode domain.BatchMode, missions []domain.Mission) ([]domain.BatchResult, error)
+}
+
+type MissionBatchUpdater interface {
+	UpdateMissions(ctx context.Context, mode domain.BatchMode, updates []domain.MissionUpdate) ([]domain.BatchResult, error)
+}
+
+type MissionBatchDeleter interface {
+	DeleteMissions(ctx context.Context, mode domain.BatchMode, ids []int64) ([]domain.BatchResult, error)
+}
+
+func BatchCreateHandler(logger *slog.Logger, missionCreator MissionBatchCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.batchCreate"
+		logger = logger.With(slog.String("op", op))
+
+		var req BatchCreateRequest
+		if err := utils.ParseJSON(r, &req); err != nil {
+			logger.Error("failed to decode request body", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		mode, err := domain.ParseBatchMode(req.Mode)
+		if err != nil {
+			logger.Error("invalid batch mode", slog.Any("error", err))
+			apierr.Write(w, err, "failed to create missions")
+			return
+		}
+
+		missions := make([]domain.Mission, len(req.Items))
+		for i, item := range req.Items {
+			missions[i] = domain.Mission{
+				CatID:    item.CatID,
+				Complete: item.Complete,
+				StartsAt: item.StartsAt,
+				DueAt:    item.DueAt,
+				Targets:  item.Targets,
+
+				Priority:       item.Priority,
+				Classification: item.Classification,
+			}
+		}
+
+		results, err := missionCreator.CreateMissions(r.Context(), mode, missions)
+		if err != nil {
+			logger.Error("failed to create missions", slog.Any("error", err))
+			apierr.Write(w, err, "failed to create missions")
+			return
+		}
+
+		logger.Info("mission batch created", slog.String("mode", string(mode)), slog.Int("items", len(results)))
+		batch.Write(w, mode, results, http.StatusCreated, "failed to create mission")
+	}
+}
+
+func BatchUpdateHandler(logger *slog.Logger, missionUpdater MissionBatchUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.batchUpdate"
+		logger = logger.With(slog.String("op", op))
+
+		var req BatchUpdateRequest
+		if err := utils.ParseJSON(r, &req); err != nil {
+			logger.Error("failed to decode request body", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("failed to decode request"))
+			return
+		}
+
+		mode, err := domain.ParseBatchMode(req.Mode)
+		if err != nil {
+			logger.Error("invalid batch mode", slog.Any("error", err))
+			apierr.Write(w, err, "failed to update missions")
+			return
+		}
+
+		updates := make([]domain.MissionUpdate, len(req.Items))
+		for i, item := range req.Items {
+			updates[i] = domain.MissionUpdate{ID: item.ID, Complete: item.Complete, CatID: item.CatID}
+		}
+
+		results, err := missionUpdater.UpdateMissions(r.Context(), mode, updates)
+		if err != nil {
+			logger.Error("failed to update missions", slog.Any("error", err))
+			apierr.Write(w, err, "failed to update missions")
+			return
+		}
+
+		logger.Info("mission batch updated", slog.String("mode", string(mode)), slog.Int("items", len(results)))
+		batch.Write(w, mode, results, http.StatusOK, "failed to update mission")
+	}
+}
+
+// BatchDeleteHandler deletes the missions listed in the ids query
+// parameter, in the batch mode given by the mode query parameter.
+func BatchDeleteHandler(logger *slog.Logger, missionDeleter MissionBatchDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.batchDelete"
+		logger = logger.With(slog.String("op", op))
+
+		ids, err := utils.ParseListParam(r, "ids", parseMissionID)
+		if err != nil {
+			logger.Error("invalid mission ids", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		mode, err := domain.ParseBatchMode(r.URL.Query().Get("mode"))
+		if err != nil {
+			logger.Error("invalid batch mode", slog.Any("error", err))
+			apierr.Write(w, err, "failed to delete missions")
+			return
+		}
+
+		results, err := missionDeleter.DeleteMissions(r.Context(), mode, ids)
+		if err != nil {
+			logger.Error("failed to delete missions", slog.Any("error", err))
+			apierr.Write(w, err, "failed to delete missions")
+			return
+		}
+
+		logger.Info("mission batch deleted", slog.String("mode", string(mode)), slog.Int("items", len(results)))
+		batch.Write(w, mode, results, http.StatusOK, "failed to delete mission")
+	}
+}
+
+func parseMissionID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("ids must be a comma separated list of mission ids")
+	}
+	return id, nil
+}
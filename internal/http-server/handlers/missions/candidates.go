@@ -0,0 +1,64 @@
+package missions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/matching"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type CandidatesGetter interface {
+	GetCandidates(ctx context.Context, missionID int64, limit int) ([]matching.Candidate, error)
+}
+
+type CandidatesResponse struct {
+	Candidates []matching.Candidate `json:"candidates"`
+}
+
+// CandidatesHandler ranks the cats that could be assigned to a mission.
+// ?limit= caps the number of candidates returned.
+func CandidatesHandler(logger *slog.Logger, candidatesGetter CandidatesGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.candidates"
+
+		logger = logger.With(slog.String("op", op))
+
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			logger.Error("invalid mission id", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid mission id"))
+			return
+		}
+
+		limit := 0
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil || limit < 1 {
+				logger.Error("invalid limit query parameter", slog.String("limit", limitStr))
+				utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid limit query parameter"))
+				return
+			}
+		}
+
+		candidates, err := candidatesGetter.GetCandidates(r.Context(), id, limit)
+		if err != nil {
+			logger.Error("failed to get mission candidates", slog.Any("error", err))
+			apierr.Write(w, err, "failed to get mission candidates")
+			return
+		}
+		if candidates == nil {
+			candidates = []matching.Candidate{}
+		}
+
+		logger.Info("mission candidates retrieved successfully", slog.Int64("missionID", id), slog.Int("count", len(candidates)))
+
+		utils.WriteJSON(w, http.StatusOK, CandidatesResponse{Candidates: candidates})
+	}
+}
@@ -0,0 +1,73 @@
+package missions
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type MissionCloner interface {
+	CloneMission(ctx context.Context, id int64) (int64, error)
+}
+
+type TemplateInstantiator interface {
+	CreateMissionFromTemplate(ctx context.Context, templateID int64) (int64, error)
+}
+
+// CloneHandler creates a new unassigned mission with copies of the targets
+// of the mission in the path.
+func CloneHandler(logger *slog.Logger, missionCloner MissionCloner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.clone"
+		logger = logger.With(slog.String("op", op))
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			logger.Error("invalid mission id", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, errors.New("invalid mission id"))
+			return
+		}
+
+		cloneID, err := missionCloner.CloneMission(r.Context(), id)
+		if err != nil {
+			logger.Error("failed to clone mission", slog.Any("error", err))
+			apierr.Write(w, err, "failed to clone mission")
+			return
+		}
+
+		logger.Info("mission cloned successfully", slog.Int64("id", id), slog.Int64("cloneID", cloneID))
+		utils.WriteJSON(w, http.StatusCreated, CreateResponse{ID: cloneID})
+	}
+}
+
+// CreateFromTemplateHandler creates a new unassigned mission from the
+// template in the path.
+func CreateFromTemplateHandler(logger *slog.Logger, templateInstantiator TemplateInstantiator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.createFromTemplate"
+		logger = logger.With(slog.String("op", op))
+
+		templateID, err := strconv.ParseInt(chi.URLParam(r, "templateID"), 10, 64)
+		if err != nil {
+			logger.Error("invalid template id", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, errors.New("invalid template id"))
+			return
+		}
+
+		id, err := templateInstantiator.CreateMissionFromTemplate(r.Context(), templateID)
+		if err != nil {
+			logger.Error("failed to create mission from template", slog.Any("error", err))
+			apierr.Write(w, err, "failed to create mission from template")
+			return
+		}
+
+		logger.Info("mission created from template successfully", slog.Int64("templateID", templateID), slog.Int64("id", id))
+		utils.WriteJSON(w, http.StatusCreated, CreateResponse{ID: id})
+	}
+}
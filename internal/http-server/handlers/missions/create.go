@@ -1,32 +1,56 @@
 package missions
 
 import (
-	"database/sql"
+	"context"
 	"log/slog"
 	"net/http"
+	"time"
 
-	"github.com/go-playground/validator/v10"
-	"github.com/illiakornyk/spy-cat/internal/common"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 )
 
 type CreateRequest struct {
-	CatID    *int64         `json:"cat_id,omitempty"`
-	Targets  []common.Target `json:"targets" validate:"required,dive"`
-	Complete bool           `json:"complete"`
+	CatID    *int64          `json:"cat_id,omitempty"`
+	Targets  []domain.Target `json:"targets"`
+	Complete bool            `json:"complete"`
+	StartsAt *time.Time      `json:"starts_at,omitempty"`
+	DueAt    *time.Time      `json:"due_at,omitempty"`
+	// Priority and Classification default to normal and public.
+	Priority       domain.Priority       `json:"priority,omitempty"`
+	Classification domain.Classification `json:"classification,omitempty"`
 }
 
 type CreateResponse struct {
 	ID int64 `json:"id,omitempty"`
 }
 
+// LogValue keeps the targets, and with them their notes, out of the log.
+func (r CreateRequest) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Bool("complete", r.Complete),
+		slog.String("priority", string(r.Priority)),
+		slog.String("classification", string(r.Classification)),
+		slog.Int("targets", len(r.Targets)),
+	}
+	if r.CatID != nil {
+		attrs = append(attrs, slog.Int64("cat_id", *r.CatID))
+	}
+	if r.StartsAt != nil {
+		attrs = append(attrs, slog.Time("starts_at", *r.StartsAt))
+	}
+	if r.DueAt != nil {
+		attrs = append(attrs, slog.Time("due_at", *r.DueAt))
+	}
+	return slog.GroupValue(attrs...)
+}
+
 type MissionCreator interface {
-	CreateMission(catID sql.NullInt64, targets []common.Target, complete bool) (int64, error)
+	CreateMission(ctx context.Context, mission domain.Mission) (int64, error)
 }
 
 func CreateHandler(logger *slog.Logger, missionCreator MissionCreator) http.HandlerFunc {
-	validate := validator.New()
-
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.missions.create"
 		logger = logger.With(slog.String("op", op))
@@ -42,24 +66,19 @@ func CreateHandler(logger *slog.Logger, missionCreator MissionCreator) http.Hand
 
 		logger.Info("request body decoded", slog.Any("req", req))
 
-		err = validate.Struct(req)
-		if err != nil {
-			logger.Error("validation failed", slog.Any("error", err))
-			utils.WriteError(w, http.StatusBadRequest, err)
-			return
-		}
-
-		var catID sql.NullInt64
-		if req.CatID != nil {
-			catID = sql.NullInt64{Int64: *req.CatID, Valid: true}
-		} else {
-			catID = sql.NullInt64{Valid: false}
-		}
+		id, err := missionCreator.CreateMission(r.Context(), domain.Mission{
+			CatID:    req.CatID,
+			Complete: req.Complete,
+			StartsAt: req.StartsAt,
+			DueAt:    req.DueAt,
+			Targets:  req.Targets,
 
-		id, err := missionCreator.CreateMission(catID, req.Targets, req.Complete)
+			Priority:       req.Priority,
+			Classification: req.Classification,
+		})
 		if err != nil {
 			logger.Error("failed to create mission", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, err)
+			apierr.Write(w, err, "failed to create mission")
 			return
 		}
 
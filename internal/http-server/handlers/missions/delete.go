@@ -1,18 +1,19 @@
 package missions
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 )
 
 type MissionDeleter interface {
-	DeleteUnassignedMission(missionIDs []int64) error
-	MissionExists(missionID int64) (bool, error)
+	DeleteMission(ctx context.Context, id int64) error
 }
 
 func DeleteHandler(logger *slog.Logger, missionDeleter MissionDeleter) http.HandlerFunc {
@@ -28,22 +29,10 @@ func DeleteHandler(logger *slog.Logger, missionDeleter MissionDeleter) http.Hand
 			return
 		}
 
-		exists, err := missionDeleter.MissionExists(id)
-		if err != nil {
-			logger.Error("failed to check if mission exists", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to check if mission exists"))
-			return
-		}
-		if !exists {
-			logger.Error("mission does not exist", slog.Int64("missionID", id))
-			utils.WriteError(w, http.StatusNotFound, errors.New("mission does not exist"))
-			return
-		}
-
-		err = missionDeleter.DeleteUnassignedMission([]int64{id})
+		err = missionDeleter.DeleteMission(r.Context(), id)
 		if err != nil {
 			logger.Error("failed to delete mission", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to delete mission"))
+			apierr.Write(w, err, "failed to delete mission")
 			return
 		}
 
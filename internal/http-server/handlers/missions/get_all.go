@@ -1,53 +1,87 @@
 package missions
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 
-	"github.com/illiakornyk/spy-cat/internal/common"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 )
 
 type MissionLister interface {
-	GetAllMissions() ([]common.Mission, error)
+	GetAllMissions(ctx context.Context, filter domain.MissionFilter) ([]domain.Mission, error)
 }
 
-type MissionResponse struct {
-			ID       int64             `json:"id"`
-			CatID    *int64            `json:"cat_id,omitempty"`
-			Complete bool              `json:"complete"`
-			Targets  []common.Target   `json:"targets"`
-		}
-
+// GetAllHandler lists missions, optionally filtered with created_after,
+// created_before, completed_after, completed_before, overdue, priority and
+// classification. The last two take comma separated lists. sort orders the
+// list by one of domain.MissionSortFields, descending when prefixed by "-".
 func GetAllHandler(logger *slog.Logger, missionLister MissionLister) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.missions.list"
 
 		logger = logger.With(slog.String("op", op))
 
-		missions, err := missionLister.GetAllMissions()
+		var filter domain.MissionFilter
+		var err error
+		if filter.Created, err = utils.ParseTimeRange(r, "created"); err == nil {
+			filter.Completed, err = utils.ParseTimeRange(r, "completed")
+		}
+		if err == nil {
+			filter.Overdue, err = utils.ParseBoolParam(r, "overdue")
+		}
+		if err == nil {
+			filter.Priorities, err = utils.ParseListParam(r, "priority", domain.ParsePriority)
+		}
+		if err == nil {
+			filter.Classifications, err = utils.ParseListParam(r, "classification", domain.ParseClassification)
+		}
+		if err == nil {
+			filter.Sort, err = parseSort(r)
+		}
+		if err != nil {
+			logger.Error("invalid filter", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		missions, err := missionLister.GetAllMissions(r.Context(), filter)
 		if err != nil {
 			logger.Error("failed to list missions", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, err)
+			apierr.Write(w, err, "failed to list missions")
 			return
 		}
 
-		var response []MissionResponse
-		for _, mission := range missions {
-			var catID *int64
-			if mission.CatID.Valid {
-				catID = &mission.CatID.Int64
-			}
-			response = append(response, MissionResponse{
-				ID:       mission.ID,
-				CatID:    catID,
-				Complete: mission.Complete,
-				Targets:  mission.Targets,
-			})
+		if missions == nil {
+			missions = []domain.Mission{}
 		}
 
 		logger.Info("missions listed successfully", slog.Int("count", len(missions)))
 
-		utils.WriteJSON(w, http.StatusOK, response)
+		utils.WriteJSON(w, http.StatusOK, missions)
+	}
+}
+
+func parseSort(r *http.Request) (domain.MissionSort, error) {
+	v := r.URL.Query().Get("sort")
+	if v == "" {
+		return domain.MissionSort{}, nil
+	}
+
+	sort := domain.MissionSort{Field: domain.MissionSortField(strings.TrimPrefix(v, "-")), Desc: strings.HasPrefix(v, "-")}
+	for _, f := range domain.MissionSortFields {
+		if f == sort.Field {
+			return sort, nil
+		}
+	}
+
+	names := make([]string, len(domain.MissionSortFields))
+	for i, f := range domain.MissionSortFields {
+		names[i] = string(f)
 	}
+	return domain.MissionSort{}, fmt.Errorf("sort must be one of %s, optionally prefixed by -", strings.Join(names, ", "))
 }
@@ -1,18 +1,24 @@
 package missions
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/illiakornyk/spy-cat/internal/common"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/geo"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 )
 
 type MissionGetter interface {
-	GetMission(id int64) (*common.Mission, error)
+	GetMission(ctx context.Context, id int64) (*domain.Mission, error)
 }
 
 func GetOneHandler(logger *slog.Logger, missionGetter MissionGetter) http.HandlerFunc {
@@ -29,20 +35,61 @@ func GetOneHandler(logger *slog.Logger, missionGetter MissionGetter) http.Handle
 			return
 		}
 
-		mission, err := missionGetter.GetMission(id)
+		mission, err := missionGetter.GetMission(r.Context(), id)
 		if err != nil {
 			logger.Error("failed to get mission", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to get mission"))
-			return
-		}
-		if mission == nil {
-			logger.Error("mission not found", slog.Int64("missionID", id))
-			utils.WriteError(w, http.StatusNotFound, fmt.Errorf("mission not found"))
+			apierr.Write(w, err, "failed to get mission")
 			return
 		}
 
 		logger.Info("mission retrieved successfully", slog.Int64("missionID", id))
 
+		if acceptsGeoJSON(r) {
+			w.Header().Set("Content-Type", geo.MediaTypeGeoJSON)
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(targetFeatures(mission))
+			return
+		}
+
 		utils.WriteJSON(w, http.StatusOK, mission)
 	}
 }
+
+// acceptsGeoJSON reports whether the client asked for GeoJSON with the
+// Accept header.
+func acceptsGeoJSON(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == geo.MediaTypeGeoJSON {
+			return true
+		}
+	}
+	return false
+}
+
+// targetFeatures renders the targets of a mission as a GeoJSON feature
+// collection. Targets without a location have no geometry.
+func targetFeatures(mission *domain.Mission) geo.FeatureCollection {
+	features := make([]geo.Feature, 0, len(mission.Targets))
+	for _, t := range mission.Targets {
+		var point *geo.Point
+		if t.Latitude != nil && t.Longitude != nil {
+			point = &geo.Point{Lat: *t.Latitude, Lon: *t.Longitude}
+		}
+
+		properties := map[string]any{
+			"mission_id":   mission.ID,
+			"name":         t.Name,
+			"country":      t.Country,
+			"country_name": t.CountryName,
+			"address":      t.Address,
+			"complete":     t.Complete,
+			"overdue":      t.Overdue,
+		}
+		if t.DueAt != nil {
+			properties["due_at"] = t.DueAt
+		}
+		features = append(features, geo.NewFeature(t.ID, point, properties))
+	}
+	return geo.NewFeatureCollection(features)
+}
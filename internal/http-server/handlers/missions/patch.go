@@ -1,29 +1,31 @@
 package missions
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 )
 
 type UpdateMissionRequest struct {
 	Complete *bool `json:"complete,omitempty"`
 	CatID    *int64 `json:"cat_id,omitempty"`
+	Priority       *domain.Priority       `json:"priority,omitempty"`
+	Classification *domain.Classification `json:"classification,omitempty"`
 }
 
 type MissionUpdater interface {
-	UpdateMissionCompleteStatus(id int64, complete bool) error
-	AssignCatToMission(missionID, catID int64) error
-	MissionExists(id int64) (bool, error)
+	UpdateMissionCompleteStatus(ctx context.Context, id int64, complete bool) error
+	AssignCatToMission(ctx context.Context, missionID, catID int64) error
+	UpdateMissionLevels(ctx context.Context, id int64, priority *domain.Priority, classification *domain.Classification) error
 }
 func UpdateHandler(logger *slog.Logger, missionUpdater MissionUpdater) http.HandlerFunc {
-	validate := validator.New()
-
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.missions.update"
 		logger = logger.With(slog.String("op", op))
@@ -36,18 +38,6 @@ func UpdateHandler(logger *slog.Logger, missionUpdater MissionUpdater) http.Hand
 			return
 		}
 
-		exists, err := missionUpdater.MissionExists(id)
-		if err != nil {
-			logger.Error("failed to check if mission exists", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to check if mission exists"))
-			return
-		}
-		if !exists {
-			logger.Error("mission does not exist", slog.Int64("missionID", id))
-			utils.WriteError(w, http.StatusNotFound, fmt.Errorf("mission does not exist"))
-			return
-		}
-
 		var req UpdateMissionRequest
 		err = utils.ParseJSON(r, &req)
 		if err != nil {
@@ -56,7 +46,9 @@ func UpdateHandler(logger *slog.Logger, missionUpdater MissionUpdater) http.Hand
 			return
 		}
 
-		if req.Complete == nil && req.CatID == nil {
+		levels := req.Priority != nil || req.Classification != nil
+
+		if req.Complete == nil && req.CatID == nil && !levels {
 			logger.Error("no update fields provided")
 			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("no update fields provided"))
 			return
@@ -68,10 +60,18 @@ func UpdateHandler(logger *slog.Logger, missionUpdater MissionUpdater) http.Hand
 			return
 		}
 
+		if levels && (req.Complete != nil || req.CatID != nil) {
+			logger.Error("cannot update priority or classification together with complete status or cat_id")
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("cannot update priority or classification together with complete status or cat_id"))
+			return
+		}
+
 		if req.Complete != nil {
 			updateCompleteStatus(w, r, id, *req.Complete, logger, missionUpdater)
 		} else if req.CatID != nil {
-			assignCat(w, r, id, *req.CatID, logger, missionUpdater, validate)
+			assignCat(w, r, id, *req.CatID, logger, missionUpdater)
+		} else {
+			updateLevels(w, r, id, req.Priority, req.Classification, logger, missionUpdater)
 		}
 	}
 }
@@ -80,17 +80,10 @@ func updateCompleteStatus(w http.ResponseWriter, r *http.Request, id int64, comp
 	const op = "handlers.missions.updateCompleteStatus"
 	logger = logger.With(slog.String("op", op))
 
-	err := missionUpdater.UpdateMissionCompleteStatus(id, complete)
+	err := missionUpdater.UpdateMissionCompleteStatus(r.Context(), id, complete)
 	if err != nil {
 		logger.Error("failed to update mission complete status", slog.Any("error", err))
-
-		// Check if the error is due to incomplete targets
-		if err.Error() == "storage.sqlite.UpdateMissionCompleteStatus: cannot complete mission until all targets are completed" {
-			utils.WriteError(w, http.StatusConflict, fmt.Errorf("cannot complete mission until all targets are completed"))
-			return
-		}
-
-		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to update mission complete status"))
+		apierr.Write(w, err, "failed to update mission complete status")
 		return
 	}
 
@@ -99,24 +92,32 @@ func updateCompleteStatus(w http.ResponseWriter, r *http.Request, id int64, comp
 }
 
 
-func assignCat(w http.ResponseWriter, r *http.Request, id int64, catID int64, logger *slog.Logger, missionUpdater MissionUpdater, validate *validator.Validate) {
+func assignCat(w http.ResponseWriter, r *http.Request, id int64, catID int64, logger *slog.Logger, missionUpdater MissionUpdater) {
 	const op = "handlers.missions.assignCat"
 	logger = logger.With(slog.String("op", op))
 
-	err := validate.Var(catID, "required,min=1")
+	err := missionUpdater.AssignCatToMission(r.Context(), id, catID)
 	if err != nil {
-		logger.Error("validation failed", slog.Any("error", err))
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("validation failed: %w", err))
+		logger.Error("failed to assign cat to mission", slog.Any("error", err))
+		apierr.Write(w, err, "failed to assign cat to mission")
 		return
 	}
 
-	err = missionUpdater.AssignCatToMission(id, catID)
+	logger.Info("cat assigned to mission successfully", slog.Int64("missionID", id), slog.Int64("catID", catID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func updateLevels(w http.ResponseWriter, r *http.Request, id int64, priority *domain.Priority, classification *domain.Classification, logger *slog.Logger, missionUpdater MissionUpdater) {
+	const op = "handlers.missions.updateLevels"
+	logger = logger.With(slog.String("op", op))
+
+	err := missionUpdater.UpdateMissionLevels(r.Context(), id, priority, classification)
 	if err != nil {
-		logger.Error("failed to assign cat to mission", slog.Any("error", err))
-		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to assign cat to mission"))
+		logger.Error("failed to update mission priority or classification", slog.Any("error", err))
+		apierr.Write(w, err, "failed to update mission priority or classification")
 		return
 	}
 
-	logger.Info("cat assigned to mission successfully", slog.Int64("missionID", id), slog.Int64("catID", catID))
+	logger.Info("mission priority and classification updated successfully", slog.Int64("id", id))
 	w.WriteHeader(http.StatusNoContent)
 }
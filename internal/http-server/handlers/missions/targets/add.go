@@ -1,30 +1,54 @@
 package targets
 
 import (
-	"fmt"
+	"context"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/logger"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 )
 
 type AddTargetRequest struct {
-	Name    string `json:"name" validate:"required,min=1,max=100"`
-	Country string `json:"country" validate:"required,min=1,max=100"`
-	Notes   string `json:"notes" validate:"omitempty,max=500"`
+	Name    string     `json:"name"`
+	Country string     `json:"country"`
+	Notes   string     `json:"notes"`
+	DueAt   *time.Time `json:"due_at,omitempty"`
+
+	Address   string   `json:"address,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// LogValue keeps the notes and the location out of the log.
+func (r AddTargetRequest) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("name", r.Name),
+		slog.String("country", r.Country),
+		slog.String("notes", logger.Redacted),
+	}
+	if r.DueAt != nil {
+		attrs = append(attrs, slog.Time("due_at", *r.DueAt))
+	}
+	if r.Address != "" {
+		attrs = append(attrs, slog.String("address", logger.Redacted))
+	}
+	if r.Latitude != nil || r.Longitude != nil {
+		attrs = append(attrs, slog.String("coordinates", logger.Redacted))
+	}
+	return slog.GroupValue(attrs...)
 }
 
 type TargetAdder interface {
-	AddTarget(missionID int64, name, country, notes string) (int64, error)
-	MissionExists(missionID int64) (bool, error)
+	AddTarget(ctx context.Context, missionID int64, target domain.Target) (int64, error)
 }
 
 func AddTargetHandler(logger *slog.Logger, targetAdder TargetAdder) http.HandlerFunc {
-	validate := validator.New()
-
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.missions.targets.add"
 		logger = logger.With(slog.String("op", op))
@@ -37,18 +61,6 @@ func AddTargetHandler(logger *slog.Logger, targetAdder TargetAdder) http.Handler
 			return
 		}
 
-		exists, err := targetAdder.MissionExists(missionID)
-		if err != nil {
-			logger.Error("failed to check if mission exists", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, err)
-			return
-		}
-		if !exists {
-			logger.Error("mission does not exist", slog.Int64("missionID", missionID))
-			utils.WriteError(w, http.StatusNotFound, fmt.Errorf("mission with ID %d does not exist", missionID))
-			return
-		}
-
 		var req AddTargetRequest
 		err = utils.ParseJSON(r, &req)
 		if err != nil {
@@ -59,17 +71,19 @@ func AddTargetHandler(logger *slog.Logger, targetAdder TargetAdder) http.Handler
 
 		logger.Info("request body decoded", slog.Any("req", req))
 
-		err = validate.Struct(req)
-		if err != nil {
-			logger.Error("validation failed", slog.Any("error", err))
-			utils.WriteError(w, http.StatusBadRequest, err)
-			return
-		}
+		targetID, err := targetAdder.AddTarget(r.Context(), missionID, domain.Target{
+			Name:    req.Name,
+			Country: req.Country,
+			Notes:   req.Notes,
+			DueAt:   req.DueAt,
 
-		targetID, err := targetAdder.AddTarget(missionID, req.Name, req.Country, req.Notes)
+			Address:   req.Address,
+			Latitude:  req.Latitude,
+			Longitude: req.Longitude,
+		})
 		if err != nil {
 			logger.Error("failed to add target", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, err)
+			apierr.Write(w, err, "failed to add target")
 			return
 		}
 
@@ -0,0 +1,195 @@
+package targets
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+// attachmentField is the multipart form field carrying an uploaded file.
+const attachmentField = "file"
+
+type AttachmentAdder interface {
+	AddAttachment(ctx context.Context, missionID, targetID int64, filename string, content io.Reader) (*domain.Attachment, error)
+}
+
+type AttachmentReader interface {
+	GetAttachments(ctx context.Context, missionID, targetID int64) ([]domain.Attachment, error)
+	OpenAttachment(ctx context.Context, missionID, targetID, attachmentID int64) (*domain.Attachment, io.ReadCloser, error)
+}
+
+type AttachmentDeleter interface {
+	DeleteAttachment(ctx context.Context, missionID, targetID, attachmentID int64) error
+}
+
+// UploadAttachmentHandler attaches the file sent in the "file" field of a
+// multipart/form-data body to a target. The file is streamed to storage
+// rather than buffered in memory.
+func UploadAttachmentHandler(logger *slog.Logger, attachmentAdder AttachmentAdder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.targets.uploadAttachment"
+		logger = logger.With(slog.String("op", op))
+
+		missionID, targetID, err := parseTargetPath(r)
+		if err != nil {
+			logger.Error("invalid path", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		part, err := filePart(r)
+		if err != nil {
+			logger.Error("invalid upload", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		defer part.Close()
+
+		attachment, err := attachmentAdder.AddAttachment(r.Context(), missionID, targetID, part.FileName(), part)
+		if err != nil {
+			logger.Error("failed to add attachment", slog.Any("error", err))
+			apierr.Write(w, err, "failed to add attachment")
+			return
+		}
+
+		logger.Info("attachment added successfully", slog.Int64("targetID", targetID), slog.Int64("attachmentID", attachment.ID),
+			slog.String("mediaType", attachment.MediaType), slog.Int64("size", attachment.Size))
+		utils.WriteJSON(w, http.StatusCreated, attachment)
+	}
+}
+
+// ListAttachmentsHandler lists the attachments of a target.
+func ListAttachmentsHandler(logger *slog.Logger, attachmentReader AttachmentReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.targets.listAttachments"
+		logger = logger.With(slog.String("op", op))
+
+		missionID, targetID, err := parseTargetPath(r)
+		if err != nil {
+			logger.Error("invalid path", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		attachments, err := attachmentReader.GetAttachments(r.Context(), missionID, targetID)
+		if err != nil {
+			logger.Error("failed to get attachments", slog.Any("error", err))
+			apierr.Write(w, err, "failed to get attachments")
+			return
+		}
+
+		if attachments == nil {
+			attachments = []domain.Attachment{}
+		}
+
+		logger.Info("attachments retrieved successfully", slog.Int64("targetID", targetID), slog.Int("count", len(attachments)))
+		utils.WriteJSON(w, http.StatusOK, attachments)
+	}
+}
+
+// DownloadAttachmentHandler sends the content of an attachment with the
+// media type detected when it was uploaded.
+func DownloadAttachmentHandler(logger *slog.Logger, attachmentReader AttachmentReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.targets.downloadAttachment"
+		logger = logger.With(slog.String("op", op))
+
+		missionID, targetID, attachmentID, err := parseAttachmentPath(r)
+		if err != nil {
+			logger.Error("invalid path", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		attachment, content, err := attachmentReader.OpenAttachment(r.Context(), missionID, targetID, attachmentID)
+		if err != nil {
+			logger.Error("failed to open attachment", slog.Any("error", err))
+			apierr.Write(w, err, "failed to open attachment")
+			return
+		}
+		defer content.Close()
+
+		w.Header().Set("Content-Type", attachment.MediaType)
+		w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("ETag", `"`+attachment.SHA256+`"`)
+		w.WriteHeader(http.StatusOK)
+
+		if _, err := io.Copy(w, content); err != nil {
+			logger.Error("failed to send attachment", slog.Any("error", err))
+			return
+		}
+
+		logger.Info("attachment sent successfully", slog.Int64("attachmentID", attachmentID))
+	}
+}
+
+// DeleteAttachmentHandler removes an attachment from a target.
+func DeleteAttachmentHandler(logger *slog.Logger, attachmentDeleter AttachmentDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.targets.deleteAttachment"
+		logger = logger.With(slog.String("op", op))
+
+		missionID, targetID, attachmentID, err := parseAttachmentPath(r)
+		if err != nil {
+			logger.Error("invalid path", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := attachmentDeleter.DeleteAttachment(r.Context(), missionID, targetID, attachmentID); err != nil {
+			logger.Error("failed to delete attachment", slog.Any("error", err))
+			apierr.Write(w, err, "failed to delete attachment")
+			return
+		}
+
+		logger.Info("attachment deleted successfully", slog.Int64("attachmentID", attachmentID))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// filePart returns the part of a multipart body holding the uploaded file,
+// skipping any other fields sent before it.
+func filePart(r *http.Request) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, errors.New("expected a multipart/form-data body")
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errors.New(`missing "` + attachmentField + `" field`)
+		}
+		if err != nil {
+			return nil, errors.New("malformed multipart body")
+		}
+		if part.FormName() == attachmentField {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+func parseAttachmentPath(r *http.Request) (missionID, targetID, attachmentID int64, err error) {
+	missionID, targetID, err = parseTargetPath(r)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	attachmentID, err = strconv.ParseInt(chi.URLParam(r, "attachmentID"), 10, 64)
+	if err != nil {
+		return 0, 0, 0, errors.New("invalid attachment id")
+	}
+	return missionID, targetID, attachmentID, nil
+}
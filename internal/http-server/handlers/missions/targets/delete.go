@@ -1,16 +1,18 @@
 package targets
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 )
 
 type TargetDeleter interface {
-	DeleteTarget(targetID int64) error
+	DeleteTarget(ctx context.Context, missionID, targetID int64) error
 }
 
 func DeleteTargetHandler(logger *slog.Logger, targetDeleter TargetDeleter) http.HandlerFunc {
@@ -19,7 +21,7 @@ func DeleteTargetHandler(logger *slog.Logger, targetDeleter TargetDeleter) http.
 		logger = logger.With(slog.String("op", op))
 
 		missionIDStr := chi.URLParam(r, "missionID")
-		_, err := strconv.ParseInt(missionIDStr, 10, 64)
+		missionID, err := strconv.ParseInt(missionIDStr, 10, 64)
 		if err != nil {
 			logger.Error("invalid mission id", slog.Any("error", err))
 			utils.WriteError(w, http.StatusBadRequest, err)
@@ -34,10 +36,10 @@ func DeleteTargetHandler(logger *slog.Logger, targetDeleter TargetDeleter) http.
 			return
 		}
 
-		err = targetDeleter.DeleteTarget(targetID)
+		err = targetDeleter.DeleteTarget(r.Context(), missionID, targetID)
 		if err != nil {
 			logger.Error("failed to delete target", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, err)
+			apierr.Write(w, err, "failed to delete target")
 			return
 		}
 
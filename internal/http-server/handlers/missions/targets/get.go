@@ -0,0 +1,140 @@
+package targets
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type TargetReader interface {
+	GetMissionTargets(ctx context.Context, missionID int64) ([]domain.Target, error)
+	GetTarget(ctx context.Context, missionID, targetID int64) (*domain.Target, error)
+}
+
+type TargetLister interface {
+	GetTargets(ctx context.Context, filter domain.TargetFilter, limit, offset int) ([]domain.Target, int, error)
+}
+
+type TargetsResponse struct {
+	Targets []domain.Target `json:"targets"`
+	Total   int             `json:"total"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+}
+
+// GetMissionTargetsHandler lists the targets of a mission.
+func GetMissionTargetsHandler(logger *slog.Logger, targetReader TargetReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.targets.list"
+		logger = logger.With(slog.String("op", op))
+
+		missionID, err := strconv.ParseInt(chi.URLParam(r, "missionID"), 10, 64)
+		if err != nil {
+			logger.Error("invalid mission id", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, errors.New("invalid mission id"))
+			return
+		}
+
+		targets, err := targetReader.GetMissionTargets(r.Context(), missionID)
+		if err != nil {
+			logger.Error("failed to get targets", slog.Any("error", err))
+			apierr.Write(w, err, "failed to get targets")
+			return
+		}
+		if targets == nil {
+			targets = []domain.Target{}
+		}
+
+		logger.Info("targets retrieved successfully", slog.Int64("missionID", missionID), slog.Int("count", len(targets)))
+		utils.WriteJSON(w, http.StatusOK, targets)
+	}
+}
+
+// GetTargetHandler returns one target of a mission. A target of another
+// mission is not found.
+func GetTargetHandler(logger *slog.Logger, targetReader TargetReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.targets.getOne"
+		logger = logger.With(slog.String("op", op))
+
+		missionID, targetID, err := parseTargetPath(r)
+		if err != nil {
+			logger.Error("invalid path", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		target, err := targetReader.GetTarget(r.Context(), missionID, targetID)
+		if err != nil {
+			logger.Error("failed to get target", slog.Any("error", err))
+			apierr.Write(w, err, "failed to get target")
+			return
+		}
+
+		logger.Info("target retrieved successfully", slog.Int64("targetID", targetID))
+		utils.WriteJSON(w, http.StatusOK, target)
+	}
+}
+
+// GetAllHandler lists targets across missions, optionally filtered with
+// country (a comma separated list of names or codes), complete, mission_id,
+// cat_id, created_after and created_before, and paginated with ?limit= and
+// ?offset=.
+func GetAllHandler(logger *slog.Logger, targetLister TargetLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.targets.list"
+		logger = logger.With(slog.String("op", op))
+
+		var filter domain.TargetFilter
+		var err error
+		if filter.Created, err = utils.ParseTimeRange(r, "created"); err == nil {
+			filter.Countries, err = utils.ParseListParam(r, "country", func(s string) (string, error) { return s, nil })
+		}
+		if err == nil {
+			filter.Complete, err = utils.ParseBoolParam(r, "complete")
+		}
+		if err == nil {
+			filter.MissionID, err = utils.ParseIDParam(r, "mission_id")
+		}
+		if err == nil {
+			filter.CatID, err = utils.ParseIDParam(r, "cat_id")
+		}
+		if err != nil {
+			logger.Error("invalid filter", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		limit, offset, err := utils.ParsePagination(r)
+		if err != nil {
+			logger.Error("invalid pagination parameters", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		targets, total, err := targetLister.GetTargets(r.Context(), filter, limit, offset)
+		if err != nil {
+			logger.Error("failed to list targets", slog.Any("error", err))
+			apierr.Write(w, err, "failed to list targets")
+			return
+		}
+		if targets == nil {
+			targets = []domain.Target{}
+		}
+
+		logger.Info("targets listed successfully", slog.Int("count", len(targets)))
+		utils.WriteJSON(w, http.StatusOK, TargetsResponse{
+			Targets: targets,
+			Total:   total,
+			Limit:   limit,
+			Offset:  offset,
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package targets
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type MoveTargetRequest struct {
+	MissionID int64 `json:"mission_id"`
+}
+
+type TargetMover interface {
+	MoveTarget(ctx context.Context, missionID, targetID, toMissionID int64) (*domain.Target, error)
+}
+
+// MoveTargetHandler transfers a target to the mission given in the body and
+// returns the moved target.
+func MoveTargetHandler(logger *slog.Logger, targetMover TargetMover) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.targets.move"
+		logger = logger.With(slog.String("op", op))
+
+		missionID, targetID, err := parseTargetPath(r)
+		if err != nil {
+			logger.Error("invalid path", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		var req MoveTargetRequest
+		if err := utils.ParseJSON(r, &req); err != nil {
+			logger.Error("failed to decode request body", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, errors.New("failed to decode request"))
+			return
+		}
+		if req.MissionID < 1 {
+			logger.Error("missing destination mission")
+			utils.WriteError(w, http.StatusBadRequest, errors.New("mission_id is required"))
+			return
+		}
+
+		target, err := targetMover.MoveTarget(r.Context(), missionID, targetID, req.MissionID)
+		if err != nil {
+			logger.Error("failed to move target", slog.Any("error", err))
+			apierr.Write(w, err, "failed to move target")
+			return
+		}
+
+		logger.Info("target moved successfully", slog.Int64("targetID", targetID),
+			slog.Int64("fromMissionID", missionID), slog.Int64("toMissionID", req.MissionID))
+		utils.WriteJSON(w, http.StatusOK, target)
+	}
+}
@@ -0,0 +1,68 @@
+package targets
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type NearbyFinder interface {
+	FindNearby(ctx context.Context, lat, lon, radiusKM float64, limit, offset int) ([]domain.NearbyTarget, int, error)
+}
+
+type NearbyResponse struct {
+	Targets []domain.NearbyTarget `json:"targets"`
+	Total   int                   `json:"total"`
+	Limit   int                   `json:"limit"`
+	Offset  int                   `json:"offset"`
+}
+
+// NearbyHandler lists the targets within radius_km of the point given by
+// lat and lon, closest first, paginated with ?limit= and ?offset=.
+func NearbyHandler(logger *slog.Logger, nearbyFinder NearbyFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.targets.nearby"
+		logger = logger.With(slog.String("op", op))
+
+		var point [3]float64
+		for i, key := range []string{"lat", "lon", "radius_km"} {
+			v, err := utils.ParseFloatParam(r, key)
+			if err != nil {
+				logger.Error("invalid query parameter", slog.Any("error", err))
+				utils.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			point[i] = v
+		}
+		lat, lon, radiusKM := point[0], point[1], point[2]
+
+		limit, offset, err := utils.ParsePagination(r)
+		if err != nil {
+			logger.Error("invalid pagination parameters", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		targets, total, err := nearbyFinder.FindNearby(r.Context(), lat, lon, radiusKM, limit, offset)
+		if err != nil {
+			logger.Error("failed to find nearby targets", slog.Any("error", err))
+			apierr.Write(w, err, "failed to find nearby targets")
+			return
+		}
+		if targets == nil {
+			targets = []domain.NearbyTarget{}
+		}
+
+		logger.Info("nearby targets retrieved successfully", slog.Float64("radiusKM", radiusKM), slog.Int("count", len(targets)))
+		utils.WriteJSON(w, http.StatusOK, NearbyResponse{
+			Targets: targets,
+			Total:   total,
+			Limit:   limit,
+			Offset:  offset,
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package targets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/logger"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type NoteHistoryReader interface {
+	GetNoteHistory(ctx context.Context, missionID, targetID int64) ([]domain.NoteRevision, error)
+	DiffNotes(ctx context.Context, missionID, targetID int64, from, to int) (*domain.NoteDiff, error)
+}
+
+type ObservationAdder interface {
+	AddObservation(ctx context.Context, missionID, targetID int64, text string) (int, error)
+}
+
+type AddObservationRequest struct {
+	Text string `json:"text"`
+}
+
+// LogValue keeps the observation out of the log.
+func (r AddObservationRequest) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("text", logger.Redacted))
+}
+
+// NoteHistoryHandler lists every revision of a target's notes, oldest first.
+func NoteHistoryHandler(logger *slog.Logger, history NoteHistoryReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.targets.noteHistory"
+		logger = logger.With(slog.String("op", op))
+
+		missionID, targetID, err := parseTargetPath(r)
+		if err != nil {
+			logger.Error("invalid path", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		revisions, err := history.GetNoteHistory(r.Context(), missionID, targetID)
+		if err != nil {
+			logger.Error("failed to get note history", slog.Any("error", err))
+			apierr.Write(w, err, "failed to get note history")
+			return
+		}
+
+		if revisions == nil {
+			revisions = []domain.NoteRevision{}
+		}
+
+		logger.Info("note history retrieved successfully", slog.Int64("targetID", targetID), slog.Int("count", len(revisions)))
+		utils.WriteJSON(w, http.StatusOK, revisions)
+	}
+}
+
+// NoteDiffHandler compares the notes at the revisions given by the from and
+// to query parameters.
+func NoteDiffHandler(logger *slog.Logger, history NoteHistoryReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.targets.noteDiff"
+		logger = logger.With(slog.String("op", op))
+
+		missionID, targetID, err := parseTargetPath(r)
+		if err != nil {
+			logger.Error("invalid path", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		from, err := parseRevision(r, "from")
+		if err != nil {
+			logger.Error("invalid revision", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		to, err := parseRevision(r, "to")
+		if err != nil {
+			logger.Error("invalid revision", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		diff, err := history.DiffNotes(r.Context(), missionID, targetID, from, to)
+		if err != nil {
+			logger.Error("failed to diff notes", slog.Any("error", err))
+			apierr.Write(w, err, "failed to diff notes")
+			return
+		}
+
+		logger.Info("notes diffed successfully", slog.Int64("targetID", targetID), slog.Int("from", from), slog.Int("to", to))
+		utils.WriteJSON(w, http.StatusOK, diff)
+	}
+}
+
+// AddObservationHandler appends an observation to a target's notes history.
+func AddObservationHandler(logger *slog.Logger, observationAdder ObservationAdder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.missions.targets.addObservation"
+		logger = logger.With(slog.String("op", op))
+
+		missionID, targetID, err := parseTargetPath(r)
+		if err != nil {
+			logger.Error("invalid path", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		var req AddObservationRequest
+		if err := utils.ParseJSON(r, &req); err != nil {
+			logger.Error("failed to decode request body", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, errors.New("failed to decode request"))
+			return
+		}
+
+		logger.Info("request body decoded", slog.Any("req", req))
+
+		revision, err := observationAdder.AddObservation(r.Context(), missionID, targetID, req.Text)
+		if err != nil {
+			logger.Error("failed to add observation", slog.Any("error", err))
+			apierr.Write(w, err, "failed to add observation")
+			return
+		}
+
+		logger.Info("observation added successfully", slog.Int64("targetID", targetID), slog.Int("revision", revision))
+		utils.WriteJSON(w, http.StatusCreated, map[string]int{"revision": revision})
+	}
+}
+
+func parseTargetPath(r *http.Request) (missionID, targetID int64, err error) {
+	missionID, err = strconv.ParseInt(chi.URLParam(r, "missionID"), 10, 64)
+	if err != nil {
+		return 0, 0, errors.New("invalid mission id")
+	}
+	targetID, err = strconv.ParseInt(chi.URLParam(r, "targetID"), 10, 64)
+	if err != nil {
+		return 0, 0, errors.New("invalid target id")
+	}
+	return missionID, targetID, nil
+}
+
+func parseRevision(r *http.Request, key string) (int, error) {
+	revision, err := strconv.Atoi(r.URL.Query().Get(key))
+	if err != nil || revision < 1 {
+		return 0, fmt.Errorf("%s must be a revision number", key)
+	}
+	return revision, nil
+}
@@ -1,6 +1,7 @@
 package targets
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log/slog"
@@ -8,24 +9,53 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/logger"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 )
 
 type UpdateRequest struct {
-	Notes    *string `json:"notes,omitempty" validate:"omitempty,max=500"`
+	Notes    *string `json:"notes,omitempty"`
 	Complete *bool   `json:"complete,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	Country  *string `json:"country,omitempty"`
+
+	Address   *string  `json:"address,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// LogValue keeps the notes and the location out of the log.
+func (r UpdateRequest) LogValue() slog.Value {
+	var attrs []slog.Attr
+	if r.Complete != nil {
+		attrs = append(attrs, slog.Bool("complete", *r.Complete))
+	}
+	if r.Notes != nil {
+		attrs = append(attrs, slog.String("notes", logger.Redacted))
+	}
+	if r.Name != nil {
+		attrs = append(attrs, slog.String("name", *r.Name))
+	}
+	if r.Country != nil {
+		attrs = append(attrs, slog.String("country", *r.Country))
+	}
+	if r.Address != nil {
+		attrs = append(attrs, slog.String("address", logger.Redacted))
+	}
+	if r.Latitude != nil || r.Longitude != nil {
+		attrs = append(attrs, slog.String("coordinates", logger.Redacted))
+	}
+	return slog.GroupValue(attrs...)
 }
 
 type TargetUpdater interface {
-	UpdateNotes(targetID int64, notes string) error
-	UpdateCompleteStatus(targetID int64, complete bool) error
-	MissionExists(missionID int64) (bool, error)
-	TargetExists(targetID int64) (bool, error)
+	UpdateTarget(ctx context.Context, missionID, targetID int64, update domain.TargetUpdate) error
+	UpdateNotes(ctx context.Context, missionID, targetID int64, notes string) error
+	UpdateCompleteStatus(ctx context.Context, missionID, targetID int64, complete bool) error
 }
 func UpdateTargetHandler(logger *slog.Logger, targetUpdater TargetUpdater) http.HandlerFunc {
-	validate := validator.New()
-
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.missions.targets.update"
 		logger = logger.With(slog.String("op", op))
@@ -38,18 +68,6 @@ func UpdateTargetHandler(logger *slog.Logger, targetUpdater TargetUpdater) http.
 			return
 		}
 
-		exists, err := targetUpdater.MissionExists(missionID)
-		if err != nil {
-			logger.Error("failed to check if mission exists", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, err)
-			return
-		}
-		if !exists {
-			logger.Error("mission does not exist", slog.Int64("missionID", missionID))
-			utils.WriteError(w, http.StatusNotFound, errors.New("mission does not exist"))
-			return
-		}
-
 		targetIDStr := chi.URLParam(r, "targetID")
 		targetID, err := strconv.ParseInt(targetIDStr, 10, 64)
 		if err != nil {
@@ -58,18 +76,6 @@ func UpdateTargetHandler(logger *slog.Logger, targetUpdater TargetUpdater) http.
 			return
 		}
 
-		exists, err = targetUpdater.TargetExists(targetID)
-		if err != nil {
-			logger.Error("failed to check if target exists", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, err)
-			return
-		}
-		if !exists {
-			logger.Error("target does not exist", slog.Int64("targetID", targetID))
-			utils.WriteError(w, http.StatusNotFound, errors.New("target does not exist"))
-			return
-		}
-
 		var req UpdateRequest
 		err = utils.ParseJSON(r, &req)
 		if errors.Is(err, io.EOF) {
@@ -85,14 +91,8 @@ func UpdateTargetHandler(logger *slog.Logger, targetUpdater TargetUpdater) http.
 
 		logger.Info("request body decoded", slog.Any("req", req))
 
-		err = validate.Struct(req)
-		if err != nil {
-			logger.Error("validation failed", slog.Any("error", err))
-			utils.WriteError(w, http.StatusBadRequest, err)
-			return
-		}
-
-		if req.Notes == nil && req.Complete == nil {
+		details := req.Name != nil || req.Country != nil || req.Address != nil || req.Latitude != nil || req.Longitude != nil
+		if req.Notes == nil && req.Complete == nil && !details {
 			logger.Error("no valid update fields provided")
 			utils.WriteError(w, http.StatusBadRequest, errors.New("no valid update fields provided"))
 			return
@@ -104,22 +104,36 @@ func UpdateTargetHandler(logger *slog.Logger, targetUpdater TargetUpdater) http.
 			return
 		}
 
+		if details && (req.Notes != nil || req.Complete != nil) {
+			logger.Error("cannot update target details together with notes or complete status")
+			utils.WriteError(w, http.StatusBadRequest, errors.New("cannot update target details together with notes or complete status"))
+			return
+		}
+
 		if req.Notes != nil {
-			updateNotes(w, r, targetID, *req.Notes, logger, targetUpdater)
+			updateNotes(w, r, missionID, targetID, *req.Notes, logger, targetUpdater)
 		} else if req.Complete != nil {
-			updateCompleteStatus(w, r, targetID, *req.Complete, logger, targetUpdater)
+			updateCompleteStatus(w, r, missionID, targetID, *req.Complete, logger, targetUpdater)
+		} else {
+			updateDetails(w, r, missionID, targetID, domain.TargetUpdate{
+				Name:      req.Name,
+				Country:   req.Country,
+				Address:   req.Address,
+				Latitude:  req.Latitude,
+				Longitude: req.Longitude,
+			}, logger, targetUpdater)
 		}
 	}
 }
 
-func updateNotes(w http.ResponseWriter, r *http.Request, targetID int64, notes string, logger *slog.Logger, targetUpdater TargetUpdater) {
+func updateNotes(w http.ResponseWriter, r *http.Request, missionID, targetID int64, notes string, logger *slog.Logger, targetUpdater TargetUpdater) {
 	const op = "handlers.targets.updateNotes"
 	logger = logger.With(slog.String("op", op))
 
-	err := targetUpdater.UpdateNotes(targetID, notes)
+	err := targetUpdater.UpdateNotes(r.Context(), missionID, targetID, notes)
 	if err != nil {
 		logger.Error("failed to update notes", slog.Any("error", err))
-		utils.WriteError(w, http.StatusInternalServerError, err)
+		apierr.Write(w, err, "failed to update notes")
 		return
 	}
 
@@ -127,14 +141,29 @@ func updateNotes(w http.ResponseWriter, r *http.Request, targetID int64, notes s
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func updateCompleteStatus(w http.ResponseWriter, r *http.Request, targetID int64, complete bool, logger *slog.Logger, targetUpdater TargetUpdater) {
+func updateDetails(w http.ResponseWriter, r *http.Request, missionID, targetID int64, update domain.TargetUpdate, logger *slog.Logger, targetUpdater TargetUpdater) {
+	const op = "handlers.targets.updateDetails"
+	logger = logger.With(slog.String("op", op))
+
+	err := targetUpdater.UpdateTarget(r.Context(), missionID, targetID, update)
+	if err != nil {
+		logger.Error("failed to update target", slog.Any("error", err))
+		apierr.Write(w, err, "failed to update target")
+		return
+	}
+
+	logger.Info("target updated successfully", slog.Int64("id", targetID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func updateCompleteStatus(w http.ResponseWriter, r *http.Request, missionID, targetID int64, complete bool, logger *slog.Logger, targetUpdater TargetUpdater) {
 	const op = "handlers.targets.updateCompleteStatus"
 	logger = logger.With(slog.String("op", op))
 
-	err := targetUpdater.UpdateCompleteStatus(targetID, complete)
+	err := targetUpdater.UpdateCompleteStatus(r.Context(), missionID, targetID, complete)
 	if err != nil {
 		logger.Error("failed to update complete status", slog.Any("error", err))
-		utils.WriteError(w, http.StatusInternalServerError, err)
+		apierr.Write(w, err, "failed to update complete status")
 		return
 	}
 
@@ -0,0 +1,57 @@
+package search
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type Searcher interface {
+	Search(ctx context.Context, q string, limit, offset int) ([]domain.SearchHit, int, error)
+}
+
+type Response struct {
+	Hits   []domain.SearchHit `json:"hits"`
+	Total  int                `json:"total"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+}
+
+// Handler searches cats, missions and targets for the words in ?q=, best
+// matches first, paginated with ?limit= and ?offset=.
+func Handler(logger *slog.Logger, searcher Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.search"
+		logger = logger.With(slog.String("op", op))
+
+		limit, offset, err := utils.ParsePagination(r)
+		if err != nil {
+			logger.Error("invalid pagination parameters", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		// The query is left out of the log, as it may quote target notes.
+		hits, total, err := searcher.Search(r.Context(), r.URL.Query().Get("q"), limit, offset)
+		if err != nil {
+			logger.Error("failed to search", slog.Any("error", err))
+			apierr.Write(w, err, "failed to search")
+			return
+		}
+		if hits == nil {
+			hits = []domain.SearchHit{}
+		}
+
+		logger.Info("search completed successfully", slog.Int("count", len(hits)), slog.Int("total", total))
+		utils.WriteJSON(w, http.StatusOK, Response{
+			Hits:   hits,
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		})
+	}
+}
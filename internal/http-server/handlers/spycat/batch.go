@@ -0,0 +1,72 @@
+package spycat
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/batch"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+// BatchCreateRequest creates Items, all or none of them in the default
+// atomic mode, as many as succeed in partial mode.
+type BatchCreateRequest struct {
+	Mode  string          `json:"mode,omitempty"`
+	Items []CreateRequest `json:"items"`
+}
+
+type SpyCatBatchCreator interface {
+	CreateCats(ctx context.Context, mode domain.BatchMode, cats []domain.SpyCat) ([]domain.BatchResult, error)
+}
+
+func BatchCreateHandler(logger *slog.Logger, spyCatCreator SpyCatBatchCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.spycat.batchCreate"
+		logger = logger.With(slog.String("op", op))
+
+		var req BatchCreateRequest
+		err := utils.ParseJSON(r, &req)
+		if errors.Is(err, io.EOF) {
+			logger.Error("request body is empty")
+			utils.WriteError(w, http.StatusBadRequest, errors.New("empty request"))
+			return
+		}
+		if err != nil {
+			logger.Error("failed to decode request body", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, errors.New("failed to decode request"))
+			return
+		}
+
+		mode, err := domain.ParseBatchMode(req.Mode)
+		if err != nil {
+			logger.Error("invalid batch mode", slog.Any("error", err))
+			apierr.Write(w, err, "failed to create spy cats")
+			return
+		}
+
+		cats := make([]domain.SpyCat, len(req.Items))
+		for i, item := range req.Items {
+			cats[i] = domain.SpyCat{
+				Name:              item.Name,
+				YearsOfExperience: item.YearsOfExperience,
+				Breed:             item.Breed,
+				Salary:            item.Salary,
+			}
+		}
+
+		results, err := spyCatCreator.CreateCats(r.Context(), mode, cats)
+		if err != nil {
+			logger.Error("failed to create spy cats", slog.Any("error", err))
+			apierr.Write(w, err, "failed to create spy cats")
+			return
+		}
+
+		logger.Info("spy cat batch processed", slog.String("mode", string(mode)), slog.Int("items", len(results)))
+		batch.Write(w, mode, results, http.StatusCreated, "failed to create spy cat")
+	}
+}
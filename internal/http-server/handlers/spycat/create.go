@@ -1,21 +1,21 @@
 package spycat
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
-	"github.com/illiakornyk/spy-cat/internal/breeds"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 )
 
 type CreateRequest struct {
-    Name              string  `json:"name" validate:"required,min=1,max=100"`
-    YearsOfExperience int     `json:"years_of_experience" validate:"min=0"`
-    Breed             string  `json:"breed" validate:"required,min=1,max=100"`
-    Salary            float64 `json:"salary" validate:"required,gt=0"`
+    Name              string  `json:"name"`
+    YearsOfExperience int     `json:"years_of_experience"`
+    Breed             string  `json:"breed"`
+    Salary            float64 `json:"salary"`
 }
 
 type CreateResponse struct {
@@ -24,12 +24,10 @@ type CreateResponse struct {
 
 
 type SpyCatCreator interface {
-    CreateCat(name string, yearsOfExperience int, breed string, salary float64) (int64, error)
+    CreateCat(ctx context.Context, name string, yearsOfExperience int, breed string, salary float64) (int64, error)
 }
 
 func CreateHandler(logger *slog.Logger, spyCatCreator SpyCatCreator) http.HandlerFunc {
-	validate := validator.New()
-
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.spycat.create"
 
@@ -51,24 +49,10 @@ func CreateHandler(logger *slog.Logger, spyCatCreator SpyCatCreator) http.Handle
 
 		logger.Info("request body decoded", slog.Any("req", req))
 
-		err = validate.Struct(req)
-		if err != nil {
-			logger.Error("validation failed", slog.Any("error", err))
-			utils.WriteError(w, http.StatusBadRequest, errors.New("validation failed"))
-			return
-		}
-
-		// Validate the breed
-		if !breeds.IsValidBreed(req.Breed) {
-			logger.Error("invalid breed", slog.String("breed", req.Breed))
-			utils.WriteError(w, http.StatusBadRequest, errors.New("invalid breed"))
-			return
-		}
-
-		id, err := spyCatCreator.CreateCat(req.Name, req.YearsOfExperience, req.Breed, req.Salary)
+		id, err := spyCatCreator.CreateCat(r.Context(), req.Name, req.YearsOfExperience, req.Breed, req.Salary)
 		if err != nil {
 			logger.Error("failed to create spy cat", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to create spy cat"))
+			apierr.Write(w, err, "failed to create spy cat")
 			return
 		}
 
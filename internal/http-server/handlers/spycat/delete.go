@@ -1,10 +1,12 @@
 package spycat
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 
 	"log/slog"
@@ -13,8 +15,7 @@ import (
 )
 
 type SpyCatDeleter interface {
-    DeleteCat(id int64) error
-	CatExists(id int64) (bool, error)
+    DeleteCat(ctx context.Context, id int64) error
 }
 
 func DeleteHandler(logger *slog.Logger, spyCatDeleter SpyCatDeleter) http.HandlerFunc {
@@ -39,23 +40,10 @@ func DeleteHandler(logger *slog.Logger, spyCatDeleter SpyCatDeleter) http.Handle
 			return
 		}
 
-		exists, err := spyCatDeleter.CatExists(id)
-		if err != nil {
-			logger.Error("failed to check if cat exists", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to check if cat exists"))
-			return
-		}
-
-		if !exists {
-			logger.Error("cat not found", slog.Int64("id", id))
-			utils.WriteError(w, http.StatusNotFound, errors.New("cat not found"))
-			return
-		}
-
-		err = spyCatDeleter.DeleteCat(id)
+		err = spyCatDeleter.DeleteCat(r.Context(), id)
 		if err != nil {
 			logger.Error("failed to delete spy cat", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to delete spy cat"))
+			apierr.Write(w, err, "failed to delete spy cat")
 			return
 		}
 
@@ -1,42 +1,50 @@
 package spycat
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 
 	"log/slog"
 )
 
 type SpyCatsGetter interface {
-    GetAllCats() ([]SpyCat, error)
-}
-
-type SpyCat struct {
-    ID               int64   `json:"id"`
-    Name             string  `json:"name"`
-    YearsOfExperience int     `json:"years_of_experience"`
-    Breed            string  `json:"breed"`
-    Salary           float64 `json:"salary"`
+    GetAllCats(ctx context.Context, filter domain.CatFilter) ([]domain.SpyCat, error)
 }
 
 type GetAllResponse struct {
-    Cats []SpyCat `json:"cats"`
+    Cats []domain.SpyCat `json:"cats"`
 }
 
+// GetAllHandler lists cats, optionally filtered with created_after and
+// created_before.
 func GetAllHandler(logger *slog.Logger, spyCatGetter SpyCatsGetter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.spycat.get_all"
 
 		logger = logger.With(slog.String("op", op))
 
-		cats, err := spyCatGetter.GetAllCats()
+		created, err := utils.ParseTimeRange(r, "created")
+		if err != nil {
+			logger.Error("invalid filter", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		cats, err := spyCatGetter.GetAllCats(r.Context(), domain.CatFilter{Created: created})
 		if err != nil {
 			logger.Error("failed to get all spy cats", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, err)
+			apierr.Write(w, err, "failed to get all spy cats")
 			return
 		}
 
+		if cats == nil {
+			cats = []domain.SpyCat{}
+		}
+
 		logger.Info("retrieved all spy cats successfully", slog.Int("count", len(cats)))
 
 		utils.WriteJSON(w, http.StatusOK, GetAllResponse{
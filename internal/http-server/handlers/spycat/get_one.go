@@ -1,24 +1,27 @@
 package spycat
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"log/slog"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 )
 
 type SpyCatGetter interface {
-    GetCatByID(id int64) (*SpyCat, error)
-	CatExists(id int64) (bool, error)
-
+    GetCatByID(ctx context.Context, id int64) (*domain.SpyCat, error)
+    GetCatProfile(ctx context.Context, id int64) (*domain.SpyCatProfile, error)
 }
 
 type GetOneResponse struct {
-    Cat *SpyCat `json:"cat,omitempty"`
+    Cat any `json:"cat,omitempty"`
 }
 
 func GetOneHandler(logger *slog.Logger, spyCatGetter SpyCatGetter) http.HandlerFunc {
@@ -42,23 +45,28 @@ func GetOneHandler(logger *slog.Logger, spyCatGetter SpyCatGetter) http.HandlerF
 			return
 		}
 
-		exists, err := spyCatGetter.CatExists(id)
-		if err != nil {
-			logger.Error("failed to check if cat exists", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to check if cat exists"))
-			return
+		expandBreed := false
+		for _, field := range strings.Split(r.URL.Query().Get("expand"), ",") {
+			switch strings.TrimSpace(field) {
+			case "":
+			case "breed":
+				expandBreed = true
+			default:
+				logger.Error("invalid expand query parameter", slog.String("expand", field))
+				utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("cannot expand %q", field))
+				return
+			}
 		}
 
-		if !exists {
-			logger.Error("cat not found", slog.Int64("id", id))
-			utils.WriteError(w, http.StatusNotFound, fmt.Errorf("cat not found"))
-			return
+		var cat any
+		if expandBreed {
+			cat, err = spyCatGetter.GetCatProfile(r.Context(), id)
+		} else {
+			cat, err = spyCatGetter.GetCatByID(r.Context(), id)
 		}
-
-		cat, err := spyCatGetter.GetCatByID(id)
 		if err != nil {
 			logger.Error("failed to get spy cat by id", slog.Any("error", err))
-			utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to get spy cat by id"))
+			apierr.Write(w, err, "failed to get spy cat by id")
 			return
 		}
 
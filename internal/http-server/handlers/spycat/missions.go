@@ -0,0 +1,68 @@
+package spycat
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type SpyCatMissionsGetter interface {
+	GetCatMissions(ctx context.Context, id int64, limit, offset int) ([]domain.Mission, int, error)
+}
+
+type MissionsResponse struct {
+	Missions []domain.Mission `json:"missions"`
+	Total    int              `json:"total"`
+	Limit    int              `json:"limit"`
+	Offset   int              `json:"offset"`
+}
+
+// MissionsHandler lists the past and current missions of a cat, paginated
+// with ?limit= and ?offset=.
+func MissionsHandler(logger *slog.Logger, missionsGetter SpyCatMissionsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.spycat.missions"
+
+		logger = logger.With(slog.String("op", op))
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || id < 1 {
+			logger.Error("invalid id path parameter", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, errors.New("invalid id path parameter"))
+			return
+		}
+
+		limit, offset, err := utils.ParsePagination(r)
+		if err != nil {
+			logger.Error("invalid pagination parameters", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		missions, total, err := missionsGetter.GetCatMissions(r.Context(), id, limit, offset)
+		if err != nil {
+			logger.Error("failed to get spy cat missions", slog.Any("error", err))
+			apierr.Write(w, err, "failed to get spy cat missions")
+			return
+		}
+		if missions == nil {
+			missions = []domain.Mission{}
+		}
+
+		logger.Info("retrieved spy cat missions successfully", slog.Int64("id", id), slog.Int("count", len(missions)))
+
+		utils.WriteJSON(w, http.StatusOK, MissionsResponse{
+			Missions: missions,
+			Total:    total,
+			Limit:    limit,
+			Offset:   offset,
+		})
+	}
+}
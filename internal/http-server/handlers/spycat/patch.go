@@ -1,6 +1,7 @@
 package spycat
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -8,23 +9,20 @@ import (
 	"log/slog"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
 	"github.com/illiakornyk/spy-cat/internal/utils"
 )
 
 type SpyCatUpdater interface {
-    UpdateCatSalary(id int64, salary float64) error
-	CatExists(id int64) (bool, error)
+    UpdateCatSalary(ctx context.Context, id int64, salary float64) error
 }
 
 type PatchRequest struct {
-    Salary float64 `json:"salary" validate:"required,gt=0"`
+    Salary float64 `json:"salary"`
 }
 
 
 func PatchHandler(logger *slog.Logger, spyCatUpdater SpyCatUpdater) http.HandlerFunc {
-	validate := validator.New()
-
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.spycat.patch"
 		logger = logger.With(slog.String("op", op))
@@ -43,17 +41,6 @@ func PatchHandler(logger *slog.Logger, spyCatUpdater SpyCatUpdater) http.Handler
 			return
 		}
 
-		exists, err := spyCatUpdater.CatExists(id)
-		if err != nil {
-			utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to check if cat exists"))
-			return
-		}
-
-		if !exists {
-			utils.WriteError(w, http.StatusNotFound, fmt.Errorf("cat not found"))
-			return
-		}
-
 		var req PatchRequest
 		err = utils.ParseJSON(r, &req)
 		if err != nil {
@@ -61,15 +48,10 @@ func PatchHandler(logger *slog.Logger, spyCatUpdater SpyCatUpdater) http.Handler
 			return
 		}
 
-		err = validate.Struct(req)
-		if err != nil {
-			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("validation failed: %w", err))
-			return
-		}
-
-		err = spyCatUpdater.UpdateCatSalary(id, req.Salary)
+		err = spyCatUpdater.UpdateCatSalary(r.Context(), id, req.Salary)
 		if err != nil {
-			utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to update spy cat salary"))
+			logger.Error("failed to update spy cat salary", slog.Any("error", err))
+			apierr.Write(w, err, "failed to update spy cat salary")
 			return
 		}
 
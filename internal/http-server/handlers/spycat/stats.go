@@ -0,0 +1,44 @@
+package spycat
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type SpyCatStatsGetter interface {
+	GetCatStats(ctx context.Context, id int64) (*domain.CatStats, error)
+}
+
+func StatsHandler(logger *slog.Logger, statsGetter SpyCatStatsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.spycat.stats"
+
+		logger = logger.With(slog.String("op", op))
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || id < 1 {
+			logger.Error("invalid id path parameter", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, errors.New("invalid id path parameter"))
+			return
+		}
+
+		stats, err := statsGetter.GetCatStats(r.Context(), id)
+		if err != nil {
+			logger.Error("failed to get spy cat stats", slog.Any("error", err))
+			apierr.Write(w, err, "failed to get spy cat stats")
+			return
+		}
+
+		logger.Info("retrieved spy cat stats successfully", slog.Int64("id", id))
+
+		utils.WriteJSON(w, http.StatusOK, stats)
+	}
+}
@@ -0,0 +1,155 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/apierr"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+type CreateRequest struct {
+	Name    string                  `json:"name"`
+	Targets []domain.TemplateTarget `json:"targets"`
+	// Priority and Classification default to normal and public.
+	Priority       domain.Priority       `json:"priority,omitempty"`
+	Classification domain.Classification `json:"classification,omitempty"`
+}
+
+// LogValue keeps the targets, and with them their notes, out of the log.
+func (r CreateRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("name", r.Name),
+		slog.String("priority", string(r.Priority)),
+		slog.String("classification", string(r.Classification)),
+		slog.Int("targets", len(r.Targets)),
+	)
+}
+
+type CreateResponse struct {
+	ID int64 `json:"id"`
+}
+
+type TemplateCreator interface {
+	CreateTemplate(ctx context.Context, template domain.MissionTemplate) (int64, error)
+}
+
+type TemplateReader interface {
+	GetTemplates(ctx context.Context) ([]domain.MissionTemplate, error)
+	GetTemplate(ctx context.Context, id int64) (*domain.MissionTemplate, error)
+}
+
+type TemplateDeleter interface {
+	DeleteTemplate(ctx context.Context, id int64) error
+}
+
+func CreateHandler(logger *slog.Logger, templateCreator TemplateCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.templates.create"
+		logger = logger.With(slog.String("op", op))
+
+		var req CreateRequest
+		if err := utils.ParseJSON(r, &req); err != nil {
+			logger.Error("failed to decode request body", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		logger.Info("request body decoded", slog.Any("req", req))
+
+		id, err := templateCreator.CreateTemplate(r.Context(), domain.MissionTemplate{
+			Name:           req.Name,
+			Priority:       req.Priority,
+			Classification: req.Classification,
+			Targets:        req.Targets,
+		})
+		if err != nil {
+			logger.Error("failed to create template", slog.Any("error", err))
+			apierr.Write(w, err, "failed to create template")
+			return
+		}
+
+		logger.Info("template created successfully", slog.Int64("id", id))
+		utils.WriteJSON(w, http.StatusCreated, CreateResponse{ID: id})
+	}
+}
+
+func GetAllHandler(logger *slog.Logger, templateReader TemplateReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.templates.list"
+		logger = logger.With(slog.String("op", op))
+
+		templates, err := templateReader.GetTemplates(r.Context())
+		if err != nil {
+			logger.Error("failed to list templates", slog.Any("error", err))
+			apierr.Write(w, err, "failed to list templates")
+			return
+		}
+		if templates == nil {
+			templates = []domain.MissionTemplate{}
+		}
+
+		logger.Info("templates listed successfully", slog.Int("count", len(templates)))
+		utils.WriteJSON(w, http.StatusOK, templates)
+	}
+}
+
+func GetOneHandler(logger *slog.Logger, templateReader TemplateReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.templates.get"
+		logger = logger.With(slog.String("op", op))
+
+		id, err := parseID(r)
+		if err != nil {
+			logger.Error("invalid template id", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		template, err := templateReader.GetTemplate(r.Context(), id)
+		if err != nil {
+			logger.Error("failed to get template", slog.Any("error", err))
+			apierr.Write(w, err, "failed to get template")
+			return
+		}
+
+		logger.Info("template retrieved successfully", slog.Int64("id", id))
+		utils.WriteJSON(w, http.StatusOK, template)
+	}
+}
+
+func DeleteHandler(logger *slog.Logger, templateDeleter TemplateDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.templates.delete"
+		logger = logger.With(slog.String("op", op))
+
+		id, err := parseID(r)
+		if err != nil {
+			logger.Error("invalid template id", slog.Any("error", err))
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := templateDeleter.DeleteTemplate(r.Context(), id); err != nil {
+			logger.Error("failed to delete template", slog.Any("error", err))
+			apierr.Write(w, err, "failed to delete template")
+			return
+		}
+
+		logger.Info("template deleted successfully", slog.Int64("id", id))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func parseID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid template id")
+	}
+	return id, nil
+}
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/illiakornyk/spy-cat/internal/auth"
+	"github.com/illiakornyk/spy-cat/internal/utils"
+)
+
+// HeaderAPIKey carries the API key of the caller.
+const HeaderAPIKey = "X-API-Key"
+
+// New resolves the principal behind every request from its API key and
+// stores it in the request context. Requests without a key run as the
+// anonymous principal; requests with an unknown key are rejected.
+func New(log *slog.Logger, authenticator *auth.Authenticator) func(next http.Handler) http.Handler {
+	log = log.With(slog.String("component", "middleware/auth"))
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := authenticator.Authenticate(r.Header.Get(HeaderAPIKey))
+			if !ok {
+				log.Warn("unknown api key", slog.String("path", r.URL.Path))
+				utils.WriteError(w, http.StatusUnauthorized, errors.New("invalid api key"))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -3,18 +3,28 @@ package router
 import (
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/illiakornyk/spy-cat/internal/auth"
+	"github.com/illiakornyk/spy-cat/internal/config"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/audit"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/breeds"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/bulk"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/health"
 	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/missions"
 	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/missions/targets"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/search"
 	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/spycat"
+	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/templates"
+	mwAuth "github.com/illiakornyk/spy-cat/internal/http-server/middleware/auth"
 	mwLogger "github.com/illiakornyk/spy-cat/internal/http-server/middleware/logger"
-	"github.com/illiakornyk/spy-cat/internal/storage/sqlite"
+	"github.com/illiakornyk/spy-cat/internal/service"
 
 	"github.com/go-chi/chi/middleware"
 )
 
-func SetupRouter(logger *slog.Logger, storage *sqlite.Storage) *chi.Mux {
+func SetupRouter(logger *slog.Logger, services *service.Services, authenticator *auth.Authenticator, breedHealth health.BreedHealthReporter, requestTimeout time.Duration) *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
@@ -22,40 +32,105 @@ func SetupRouter(logger *slog.Logger, storage *sqlite.Storage) *chi.Mux {
 	router.Use(mwLogger.New(logger))
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
+	router.Use(mwAuth.New(logger, authenticator))
 
-	setupRoutes(router, logger, storage)
+	// Attachment uploads and downloads, exports and imports stream for as
+	// long as the data and the client take, so they have no request
+	// deadline.
+	router.Post("/api/v1/missions/{missionID}/targets/{targetID}/attachments", targets.UploadAttachmentHandler(logger, services.Attachments))
+	router.Get("/api/v1/missions/{missionID}/targets/{targetID}/attachments/{attachmentID}", targets.DownloadAttachmentHandler(logger, services.Attachments))
+	router.Get("/api/v1/export", bulk.ExportHandler(logger, services.Transfer))
+	router.Post("/api/v1/import", bulk.ImportHandler(logger, services.Transfer))
+
+	router.Group(func(r chi.Router) {
+		if requestTimeout > 0 {
+			// Bounds the request context, so storage calls made on behalf
+			// of a slow request are cancelled instead of running on after
+			// the client has given up.
+			r.Use(middleware.Timeout(requestTimeout))
+		}
+
+		setupRoutes(r, logger, services)
+		r.Get("/api/v1/health", health.Handler(logger, breedHealth))
+	})
 
 	return router
 }
 
-func setupRoutes(router *chi.Mux, logger *slog.Logger, storage *sqlite.Storage) {
+func setupRoutes(router chi.Router, logger *slog.Logger, services *service.Services) {
+	router.Route("/api/v1/breeds", func(r chi.Router) {
+		r.Get("/", breeds.GetAllHandler(logger, services.Breeds))
+		r.Get("/{id}", breeds.GetOneHandler(logger, services.Breeds))
+	})
+
 	router.Route("/api/v1/spy-cats", func(r chi.Router) {
-		r.Get("/", spycat.GetAllHandler(logger, storage))
-		r.Post("/", spycat.CreateHandler(logger, storage))
-		r.Delete("/{id}", spycat.DeleteHandler(logger, storage))
-		r.Patch("/{id}", spycat.PatchHandler(logger, storage))
-		r.Get("/{id}", spycat.GetOneHandler(logger, storage))
+		r.Get("/", spycat.GetAllHandler(logger, services.Cats))
+		r.Post("/", spycat.CreateHandler(logger, services.Cats))
+		r.Delete("/{id}", spycat.DeleteHandler(logger, services.Cats))
+		r.Patch("/{id}", spycat.PatchHandler(logger, services.Cats))
+		r.Get("/{id}", spycat.GetOneHandler(logger, services.Cats))
+		r.Get("/{id}/missions", spycat.MissionsHandler(logger, services.Cats))
+		r.Get("/{id}/stats", spycat.StatsHandler(logger, services.Cats))
+	})
+	router.Post("/api/v1/spy-cats:batch", spycat.BatchCreateHandler(logger, services.Cats))
+
+	router.Route("/api/v1/targets", func(r chi.Router) {
+		r.Get("/", targets.GetAllHandler(logger, services.Targets))
+		r.Get("/nearby", targets.NearbyHandler(logger, services.Targets))
 	})
 
+	router.Get("/api/v1/audit", audit.GetAllHandler(logger, services.Audit))
+	router.Get("/api/v1/search", search.Handler(logger, services.Search))
+
+	router.Route("/api/v1/mission-templates", func(r chi.Router) {
+		r.Post("/", templates.CreateHandler(logger, services.Missions))
+		r.Get("/", templates.GetAllHandler(logger, services.Missions))
+		r.Get("/{id}", templates.GetOneHandler(logger, services.Missions))
+		r.Delete("/{id}", templates.DeleteHandler(logger, services.Missions))
+	})
+
+	router.Post("/api/v1/missions:batch", missions.BatchCreateHandler(logger, services.Missions))
+	router.Patch("/api/v1/missions:batch", missions.BatchUpdateHandler(logger, services.Missions))
+	router.Delete("/api/v1/missions:batch", missions.BatchDeleteHandler(logger, services.Missions))
+
 	router.Route("/api/v1/missions", func(r chi.Router) {
-		r.Post("/", missions.CreateHandler(logger, storage))
-		r.Get("/", missions.GetAllHandler(logger, storage))
-		r.Get("/{id}", missions.GetOneHandler(logger, storage))
-		r.Patch("/{id}", missions.UpdateHandler(logger, storage))
-		r.Delete("/{id}", missions.DeleteHandler(logger, storage))
+		r.Post("/", missions.CreateHandler(logger, services.Missions))
+		r.Get("/", missions.GetAllHandler(logger, services.Missions))
+		r.Get("/{id}", missions.GetOneHandler(logger, services.Missions))
+		r.Patch("/{id}", missions.UpdateHandler(logger, services.Missions))
+		r.Delete("/{id}", missions.DeleteHandler(logger, services.Missions))
+		r.Get("/{id}/candidates", missions.CandidatesHandler(logger, services.Matching))
+		r.Post("/{id}/auto-assign", missions.AutoAssignHandler(logger, services.Matching))
+		r.Post("/{id}/clone", missions.CloneHandler(logger, services.Missions))
+		r.Post("/from-template/{templateID}", missions.CreateFromTemplateHandler(logger, services.Missions))
 
 		// Target routes
 		r.Route("/{missionID}/targets", func(r chi.Router) {
-			r.Patch("/{targetID}", targets.UpdateTargetHandler(logger, storage))
-			r.Delete("/{targetID}", targets.DeleteTargetHandler(logger, storage))
-			r.Post("/", targets.AddTargetHandler(logger, storage))
+			r.Get("/", targets.GetMissionTargetsHandler(logger, services.Targets))
+			r.Get("/{targetID}", targets.GetTargetHandler(logger, services.Targets))
+			r.Patch("/{targetID}", targets.UpdateTargetHandler(logger, services.Targets))
+			r.Delete("/{targetID}", targets.DeleteTargetHandler(logger, services.Targets))
+			r.Post("/", targets.AddTargetHandler(logger, services.Targets))
+			r.Post("/{targetID}/move", targets.MoveTargetHandler(logger, services.Targets))
+			r.Get("/{targetID}/notes/history", targets.NoteHistoryHandler(logger, services.Targets))
+			r.Get("/{targetID}/notes/diff", targets.NoteDiffHandler(logger, services.Targets))
+			r.Post("/{targetID}/notes/observations", targets.AddObservationHandler(logger, services.Targets))
+			r.Get("/{targetID}/attachments", targets.ListAttachmentsHandler(logger, services.Attachments))
+			r.Delete("/{targetID}/attachments/{attachmentID}", targets.DeleteAttachmentHandler(logger, services.Attachments))
 		})
 	})
 }
 
-func StartServer(address string, router *chi.Mux, logger *slog.Logger) {
-	logger.Info("Starting server", slog.String("address", address))
-	if err := http.ListenAndServe(address, router); err != nil {
+func StartServer(cfg config.HTTPServer, router *chi.Mux, logger *slog.Logger) {
+	server := &http.Server{
+		Addr:        cfg.Address,
+		Handler:     router,
+		ReadTimeout: cfg.ReadTimeout,
+		IdleTimeout: cfg.IdleTimeout,
+	}
+
+	logger.Info("Starting server", slog.String("address", cfg.Address))
+	if err := server.ListenAndServe(); err != nil {
 		logger.Error("Failed to start server", slog.Any("error", err))
 	}
 }
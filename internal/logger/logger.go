@@ -11,17 +11,36 @@ const (
 	envProd  = "prod"
 )
 
+// Redacted replaces sensitive values in log output.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose values never reach the log, at any
+// level of nesting.
+var sensitiveKeys = map[string]bool{
+	"notes": true,
+}
+
 func SetupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
 	switch env {
 	case envLocal:
-		log = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		log = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: redact}))
 	case envDev:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: redact}))
 	case envProd:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo, ReplaceAttr: redact}))
 	}
 
 	return log
 }
+
+// redact hides the values of sensitive attributes. Values logged as whole
+// structs are not inspected, so types carrying sensitive fields implement
+// slog.LogValuer as well.
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[a.Key] {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
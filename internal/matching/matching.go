@@ -0,0 +1,247 @@
+// Package matching ranks cats as candidates for a mission. Each cat is
+// scored on several factors, each normalised to the range 0..1, and the
+// factors are combined with configurable weights.
+package matching
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/illiakornyk/spy-cat/internal/config"
+	"github.com/illiakornyk/spy-cat/internal/countries"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+const (
+	defaultExperienceCapYears = 10
+
+	// neutral is the factor score used when there is nothing to judge a cat
+	// by, such as a completion rate for a cat that never had a mission.
+	neutral = 0.5
+)
+
+var (
+	defaultWeights = config.MatchingWeights{
+		Experience:         0.3,
+		CompletionRate:     0.25,
+		CountryFamiliarity: 0.2,
+		BreedTraits:        0.1,
+		SalaryCost:         0.15,
+	}
+	defaultBreedTraits = []string{"intelligence", "energy_level", "adaptability"}
+)
+
+// CatProfile is everything the scorer knows about a cat.
+type CatProfile struct {
+	Cat    domain.SpyCat
+	Breed  *domain.Breed
+	Record domain.CatTrackRecord
+}
+
+// Breakdown holds the weighted contribution of each factor to a score.
+type Breakdown struct {
+	Experience         float64 `json:"experience"`
+	CompletionRate     float64 `json:"completion_rate"`
+	CountryFamiliarity float64 `json:"country_familiarity"`
+	BreedTraits        float64 `json:"breed_traits"`
+	SalaryCost         float64 `json:"salary_cost"`
+}
+
+// Candidate is a cat ranked for a mission. Score is between 0 and 1 and is
+// the sum of the Breakdown.
+type Candidate struct {
+	Cat       domain.SpyCat `json:"cat"`
+	Score     float64       `json:"score"`
+	Breakdown Breakdown     `json:"breakdown"`
+}
+
+// Scorer ranks candidates using weights built from configuration.
+type Scorer struct {
+	weights            config.MatchingWeights
+	experienceCapYears int
+	breedTraits        []string
+}
+
+// New builds a Scorer from cfg. If no weight is configured the default
+// weights are used. Weights are normalised, so only their ratios matter.
+func New(cfg config.Matching) *Scorer {
+	s := &Scorer{
+		weights:            cfg.Weights,
+		experienceCapYears: cfg.ExperienceCapYears,
+		breedTraits:        cfg.BreedTraits,
+	}
+
+	w := s.weights
+	total := w.Experience + w.CompletionRate + w.CountryFamiliarity + w.BreedTraits + w.SalaryCost
+	if total <= 0 {
+		w = defaultWeights
+		total = 1
+	}
+	s.weights = config.MatchingWeights{
+		Experience:         w.Experience / total,
+		CompletionRate:     w.CompletionRate / total,
+		CountryFamiliarity: w.CountryFamiliarity / total,
+		BreedTraits:        w.BreedTraits / total,
+		SalaryCost:         w.SalaryCost / total,
+	}
+
+	if s.experienceCapYears <= 0 {
+		s.experienceCapYears = defaultExperienceCapYears
+	}
+	if len(s.breedTraits) == 0 {
+		s.breedTraits = defaultBreedTraits
+	}
+
+	return s
+}
+
+// Rank scores every cat for a mission with targets in countries and returns
+// them best first. Salary cost is judged relative to the other cats.
+func (s *Scorer) Rank(countries []string, cats []CatProfile) []Candidate {
+	minSalary, maxSalary := salaryRange(cats)
+
+	candidates := make([]Candidate, 0, len(cats))
+	for _, c := range cats {
+		b := Breakdown{
+			Experience:         s.weights.Experience * s.experience(c.Cat),
+			CompletionRate:     s.weights.CompletionRate * completionRate(c.Record),
+			CountryFamiliarity: s.weights.CountryFamiliarity * countryFamiliarity(c.Record, countries),
+			BreedTraits:        s.weights.BreedTraits * s.breedTraitScore(c.Breed),
+			SalaryCost:         s.weights.SalaryCost * salaryCost(c.Cat.Salary, minSalary, maxSalary),
+		}
+		candidates = append(candidates, Candidate{
+			Cat:       c.Cat,
+			Score:     round(b.Experience + b.CompletionRate + b.CountryFamiliarity + b.BreedTraits + b.SalaryCost),
+			Breakdown: b.rounded(),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Cat.ID < candidates[j].Cat.ID
+	})
+	return candidates
+}
+
+func (s *Scorer) experience(cat domain.SpyCat) float64 {
+	if cat.YearsOfExperience >= s.experienceCapYears {
+		return 1
+	}
+	return float64(cat.YearsOfExperience) / float64(s.experienceCapYears)
+}
+
+func completionRate(r domain.CatTrackRecord) float64 {
+	finished := r.Missions - r.ActiveMissions
+	if finished <= 0 {
+		return neutral
+	}
+	return float64(r.CompletedMissions) / float64(finished)
+}
+
+// countryFamiliarity is the share of the mission's countries the cat has
+// operated in before.
+func countryFamiliarity(r domain.CatTrackRecord, countries []string) float64 {
+	wanted := make(map[string]struct{}, len(countries))
+	for _, c := range countries {
+		wanted[normalizeCountry(c)] = struct{}{}
+	}
+	if len(wanted) == 0 {
+		return 0
+	}
+
+	known := 0
+	for _, c := range r.Countries {
+		if _, ok := wanted[normalizeCountry(c)]; ok {
+			known++
+			delete(wanted, normalizeCountry(c))
+		}
+	}
+	return float64(known) / float64(known+len(wanted))
+}
+
+// breedTraitScore averages the configured breed traits, which range from
+// 1 to 5. Breeds without trait data score neutral.
+func (s *Scorer) breedTraitScore(breed *domain.Breed) float64 {
+	if breed == nil {
+		return neutral
+	}
+
+	sum, n := 0, 0
+	for _, trait := range s.breedTraits {
+		if v := traitLevel(breed, trait); v > 0 {
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return neutral
+	}
+	return (float64(sum)/float64(n) - 1) / 4
+}
+
+func traitLevel(b *domain.Breed, trait string) int {
+	switch trait {
+	case "adaptability":
+		return b.Adaptability
+	case "affection_level":
+		return b.AffectionLevel
+	case "energy_level":
+		return b.EnergyLevel
+	case "intelligence":
+		return b.Intelligence
+	case "social_needs":
+		return b.SocialNeeds
+	case "stranger_friendly":
+		return b.StrangerFriendly
+	case "vocalisation":
+		return b.Vocalisation
+	default:
+		return 0
+	}
+}
+
+// salaryCost scores the cheapest cat 1 and the most expensive 0.
+func salaryCost(salary, min, max float64) float64 {
+	if max <= min {
+		return 1
+	}
+	return (max - salary) / (max - min)
+}
+
+func salaryRange(cats []CatProfile) (min, max float64) {
+	for i, c := range cats {
+		if i == 0 || c.Cat.Salary < min {
+			min = c.Cat.Salary
+		}
+		if i == 0 || c.Cat.Salary > max {
+			max = c.Cat.Salary
+		}
+	}
+	return min, max
+}
+
+func (b Breakdown) rounded() Breakdown {
+	return Breakdown{
+		Experience:         round(b.Experience),
+		CompletionRate:     round(b.CompletionRate),
+		CountryFamiliarity: round(b.CountryFamiliarity),
+		BreedTraits:        round(b.BreedTraits),
+		SalaryCost:         round(b.SalaryCost),
+	}
+}
+
+func round(v float64) float64 {
+	return float64(int64(v*1000+0.5)) / 1000
+}
+
+// normalizeCountry resolves country to its ISO 3166-1 alpha-2 code, so
+// "UK" and "GB" compare equal. Unknown countries, which may remain from
+// before countries were validated, are compared ignoring case.
+func normalizeCountry(country string) string {
+	if c, ok := countries.Lookup(country); ok {
+		return c.Alpha2
+	}
+	return strings.ToLower(strings.TrimSpace(country))
+}
@@ -0,0 +1,195 @@
+// Package rules evaluates the configurable business limits placed on
+// missions, such as how many targets a mission may have or how many active
+// missions a cat may be assigned to at once.
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/illiakornyk/spy-cat/internal/config"
+	"github.com/illiakornyk/spy-cat/internal/countries"
+)
+
+// Rule names reported in a Violation.
+const (
+	RuleMinTargets            = "min_targets"
+	RuleMaxTargets            = "max_targets"
+	RuleMaxActiveMissions     = "max_active_missions_per_cat"
+	RuleCountryExperience     = "country_experience"
+	RuleFreezeNotesOnComplete = "freeze_notes_on_complete"
+)
+
+const (
+	defaultMinTargets              = 1
+	defaultMaxTargets              = 3
+	defaultMaxActiveMissionsPerCat = 1
+)
+
+// Violation is returned when an operation breaks a business rule. Rule holds
+// the name of the broken rule so clients can react to it programmatically.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"error"`
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("rule %s violated: %s", v.Rule, v.Message)
+}
+
+func violation(rule, format string, args ...any) *Violation {
+	return &Violation{Rule: rule, Message: fmt.Sprintf(format, args...)}
+}
+
+type countryExperience struct {
+	countries map[string]struct{}
+	minYears  int
+}
+
+// Engine evaluates rules built from configuration.
+type Engine struct {
+	minTargets              int
+	maxTargets              int
+	maxActiveMissionsPerCat int
+	countryExperience       []countryExperience
+	freezeNotesOnComplete   bool
+}
+
+// New builds an Engine from cfg, falling back to the historical limits of
+// 1 to 3 targets per mission and one active mission per cat for the limits
+// cfg leaves unset. It reports an error when the limits contradict each
+// other, since no mission could ever satisfy them.
+func New(cfg config.Rules) (*Engine, error) {
+	const op = "rules.New"
+
+	e := &Engine{
+		minTargets:              defaultMinTargets,
+		maxTargets:              defaultMaxTargets,
+		maxActiveMissionsPerCat: defaultMaxActiveMissionsPerCat,
+		freezeNotesOnComplete:   true,
+	}
+
+	if cfg.MinTargets != nil {
+		e.minTargets = *cfg.MinTargets
+	}
+	if cfg.MaxTargets != nil {
+		e.maxTargets = *cfg.MaxTargets
+	}
+	if cfg.MaxActiveMissionsPerCat != nil {
+		e.maxActiveMissionsPerCat = *cfg.MaxActiveMissionsPerCat
+	}
+
+	if e.minTargets < 0 {
+		return nil, fmt.Errorf("%s: %s must not be negative, got %d", op, RuleMinTargets, e.minTargets)
+	}
+	if e.maxTargets < 1 {
+		return nil, fmt.Errorf("%s: %s must be at least 1, got %d", op, RuleMaxTargets, e.maxTargets)
+	}
+	if e.minTargets > e.maxTargets {
+		return nil, fmt.Errorf("%s: %s (%d) must not exceed %s (%d)", op, RuleMinTargets, e.minTargets, RuleMaxTargets, e.maxTargets)
+	}
+	if e.maxActiveMissionsPerCat < 1 {
+		return nil, fmt.Errorf("%s: %s must be at least 1, got %d", op, RuleMaxActiveMissions, e.maxActiveMissionsPerCat)
+	}
+
+	if cfg.FreezeNotesOnComplete != nil {
+		e.freezeNotesOnComplete = *cfg.FreezeNotesOnComplete
+	}
+
+	for _, ce := range cfg.CountryExperience {
+		countries := make(map[string]struct{}, len(ce.Countries))
+		for _, c := range ce.Countries {
+			countries[normalizeCountry(c)] = struct{}{}
+		}
+		e.countryExperience = append(e.countryExperience, countryExperience{
+			countries: countries,
+			minYears:  ce.MinYears,
+		})
+	}
+
+	return e, nil
+}
+
+// MinTargets is the fewest targets a mission may have.
+func (e *Engine) MinTargets() int {
+	return e.minTargets
+}
+
+// MaxTargets is the most targets a mission may have.
+func (e *Engine) MaxTargets() int {
+	return e.maxTargets
+}
+
+// CheckTargetCount verifies that a mission with count targets is within
+// the configured bounds.
+func (e *Engine) CheckTargetCount(count int) error {
+	if count < e.minTargets {
+		return violation(RuleMinTargets, "a mission must have at least %d target(s), got %d", e.minTargets, count)
+	}
+	if count > e.maxTargets {
+		return violation(RuleMaxTargets, "a mission can have at most %d target(s), got %d", e.maxTargets, count)
+	}
+	return nil
+}
+
+// CheckCanAddTarget verifies that one more target fits into a mission that
+// currently has count targets.
+func (e *Engine) CheckCanAddTarget(count int) error {
+	if count >= e.maxTargets {
+		return violation(RuleMaxTargets, "mission already has the maximum number of targets (%d)", e.maxTargets)
+	}
+	return nil
+}
+
+// CheckCanRemoveTarget verifies that a mission with count targets stays at
+// or above the minimum after losing one.
+func (e *Engine) CheckCanRemoveTarget(count int) error {
+	if count-1 < e.minTargets {
+		return violation(RuleMinTargets, "mission must keep at least %d target(s)", e.minTargets)
+	}
+	return nil
+}
+
+// CheckCatAssignment verifies that a cat currently on activeMissions
+// incomplete missions may take on another one.
+func (e *Engine) CheckCatAssignment(activeMissions int) error {
+	if activeMissions >= e.maxActiveMissionsPerCat {
+		return violation(RuleMaxActiveMissions, "cat is already assigned to %d active mission(s), the limit is %d", activeMissions, e.maxActiveMissionsPerCat)
+	}
+	return nil
+}
+
+// CheckCountryExperience verifies that a cat with yearsOfExperience may
+// operate in every one of countries.
+func (e *Engine) CheckCountryExperience(yearsOfExperience int, countries []string) error {
+	for _, ce := range e.countryExperience {
+		if yearsOfExperience >= ce.minYears {
+			continue
+		}
+		for _, c := range countries {
+			if _, ok := ce.countries[normalizeCountry(c)]; ok {
+				return violation(RuleCountryExperience, "missions in %s require at least %d year(s) of experience", c, ce.minYears)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckNotesEditable verifies that the notes of a target may still change
+// given the completion state of the target and its mission.
+func (e *Engine) CheckNotesEditable(targetComplete, missionComplete bool) error {
+	if e.freezeNotesOnComplete && (targetComplete || missionComplete) {
+		return violation(RuleFreezeNotesOnComplete, "cannot update notes for a completed target or mission")
+	}
+	return nil
+}
+
+// normalizeCountry resolves country to its ISO 3166-1 alpha-2 code, so
+// "UK" and "GB" compare equal. Unknown countries, which may remain from
+// before countries were validated, are compared ignoring case.
+func normalizeCountry(country string) string {
+	if c, ok := countries.Lookup(country); ok {
+		return c.Alpha2
+	}
+	return strings.ToLower(strings.TrimSpace(country))
+}
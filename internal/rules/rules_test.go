@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/config"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestNewDefaults(t *testing.T) {
+	e, err := New(config.Rules{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.MinTargets() != defaultMinTargets || e.MaxTargets() != defaultMaxTargets {
+		t.Errorf("targets %d to %d, want %d to %d", e.MinTargets(), e.MaxTargets(), defaultMinTargets, defaultMaxTargets)
+	}
+	if e.maxActiveMissionsPerCat != defaultMaxActiveMissionsPerCat {
+		t.Errorf("max active missions %d, want %d", e.maxActiveMissionsPerCat, defaultMaxActiveMissionsPerCat)
+	}
+}
+
+func TestNewKeepsZeroMinTargets(t *testing.T) {
+	e, err := New(config.Rules{MinTargets: intPtr(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.MinTargets() != 0 {
+		t.Fatalf("min targets %d, want 0", e.MinTargets())
+	}
+	if err := e.CheckTargetCount(0); err != nil {
+		t.Errorf("mission without targets: %v", err)
+	}
+	if err := e.CheckCanRemoveTarget(1); err != nil {
+		t.Errorf("remove the last target: %v", err)
+	}
+}
+
+func TestNewRejectsInvalidLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Rules
+	}{
+		{"min above max", config.Rules{MinTargets: intPtr(4), MaxTargets: intPtr(3)}},
+		{"min above default max", config.Rules{MinTargets: intPtr(5)}},
+		{"default min above max", config.Rules{MaxTargets: intPtr(0)}},
+		{"negative min", config.Rules{MinTargets: intPtr(-1)}},
+		{"zero active missions", config.Rules{MaxActiveMissionsPerCat: intPtr(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Error("New accepted the limits")
+			}
+		})
+	}
+}
+
+func TestCheckCanRemoveTarget(t *testing.T) {
+	e, err := New(config.Rules{MinTargets: intPtr(2), MaxTargets: intPtr(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.CheckCanRemoveTarget(3); err != nil {
+		t.Errorf("remove down to the minimum: %v", err)
+	}
+
+	err = e.CheckCanRemoveTarget(2)
+	var violation *Violation
+	if !errors.As(err, &violation) || violation.Rule != RuleMinTargets {
+		t.Errorf("remove below the minimum: got %v, want a %s violation", err, RuleMinTargets)
+	}
+}
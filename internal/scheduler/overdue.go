@@ -0,0 +1,96 @@
+// Package scheduler runs periodic background jobs.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/events"
+)
+
+const defaultOverdueCheckInterval = time.Minute
+
+// OverdueFlagger marks missions that passed their due time as overdue and
+// returns those that were not flagged before.
+type OverdueFlagger interface {
+	FlagOverdueMissions(ctx context.Context, asOf time.Time) ([]domain.OverdueMission, error)
+}
+
+// OverdueScheduler periodically flags overdue missions and publishes a
+// MissionOverdue event for each of them. Every mission is reported once.
+type OverdueScheduler struct {
+	store     OverdueFlagger
+	publisher events.Publisher
+	interval  time.Duration
+	logger    *slog.Logger
+}
+
+// NewOverdueScheduler builds a scheduler that checks every interval. A zero
+// interval checks every minute.
+func NewOverdueScheduler(store OverdueFlagger, publisher events.Publisher, interval time.Duration, logger *slog.Logger) *OverdueScheduler {
+	if interval <= 0 {
+		interval = defaultOverdueCheckInterval
+	}
+
+	return &OverdueScheduler{
+		store:     store,
+		publisher: publisher,
+		interval:  interval,
+		logger:    logger.With(slog.String("component", "overdue_scheduler")),
+	}
+}
+
+// Start checks for overdue missions right away and then every interval in
+// the background until ctx is cancelled.
+func (s *OverdueScheduler) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+func (s *OverdueScheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Check(ctx); err != nil {
+			s.logger.Error("failed to check overdue missions", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check flags the missions overdue now and publishes their events.
+func (s *OverdueScheduler) Check(ctx context.Context) error {
+	const op = "scheduler.OverdueScheduler.Check"
+
+	now := time.Now()
+	missions, err := s.store.FlagOverdueMissions(ctx, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, m := range missions {
+		dueAt := m.DueAt
+		event := events.Event{
+			Type:       events.MissionOverdue,
+			MissionID:  m.MissionID,
+			CatID:      m.CatID,
+			DueAt:      &dueAt,
+			OccurredAt: now,
+		}
+		if err := s.publisher.Publish(ctx, event); err != nil {
+			s.logger.Error("failed to publish event",
+				slog.Int64("mission_id", m.MissionID),
+				slog.Any("error", err),
+			)
+		}
+	}
+	return nil
+}
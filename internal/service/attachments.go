@@ -0,0 +1,343 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/illiakornyk/spy-cat/internal/auth"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+// DefaultMaxAttachmentSize is the size limit of an attachment when none is
+// configured.
+const DefaultMaxAttachmentSize = 10 << 20
+
+// DefaultAttachmentTypes are the media types accepted when none are
+// configured: common photo formats, PDF and plain text.
+var DefaultAttachmentTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"image/heic",
+	"application/pdf",
+	"text/plain",
+}
+
+// sniffLen is how much of an upload is read to detect its media type.
+const sniffLen = 3072
+
+// AttachmentOptions limits what may be attached to a target. Zero values
+// fall back to DefaultMaxAttachmentSize and DefaultAttachmentTypes.
+type AttachmentOptions struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+// AttachmentService manages files attached to targets. Metadata is kept in
+// the store and content in the blob store.
+type AttachmentService struct {
+	store   storage.Store
+	blobs   storage.BlobStore
+	maxSize int64
+	allowed []string
+}
+
+func NewAttachmentService(store storage.Store, blobs storage.BlobStore, opts AttachmentOptions) *AttachmentService {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMaxAttachmentSize
+	}
+	if len(opts.AllowedTypes) == 0 {
+		opts.AllowedTypes = DefaultAttachmentTypes
+	}
+	return &AttachmentService{store: store, blobs: blobs, maxSize: opts.MaxSize, allowed: opts.AllowedTypes}
+}
+
+// AddAttachment stores content as an attachment of the target. The media
+// type is detected from the content rather than taken from the client, and
+// must be one of the allowed types. Attachments are frozen once the target
+// or its mission is complete.
+func (s *AttachmentService) AddAttachment(ctx context.Context, missionID, targetID int64, filename string, content io.Reader) (*domain.Attachment, error) {
+	const op = "service.AttachmentService.AddAttachment"
+
+	filename = cleanFilename(filename)
+	if err := validateVar("filename", filename, "required,max=255"); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Checked before reading the upload, and again when it is recorded in
+	// case the target was completed in the meantime.
+	if err := checkAttachmentsWritable(ctx, s.store, missionID, targetID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("%s: read upload: %w", op, err)
+	}
+	head = head[:n]
+
+	mediaType := mimetype.Detect(head)
+	if !s.isAllowed(mediaType) {
+		return nil, fmt.Errorf("%s: %w: %s", op, domain.ErrUnsupportedMediaType, mediaType.String())
+	}
+
+	key, size, err := s.blobs.Put(ctx, &sizeLimitedReader{r: io.MultiReader(bytes.NewReader(head), content), remaining: s.maxSize})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attachment := domain.Attachment{
+		TargetID:   targetID,
+		Filename:   filename,
+		MediaType:  mediaType.String(),
+		Size:       size,
+		SHA256:     key,
+		UploadedBy: auth.FromContext(ctx).Name,
+	}
+
+	var id int64
+	err = s.store.WithTx(ctx, func(tx storage.Store) error {
+		if err := checkAttachmentsWritable(ctx, tx, missionID, targetID); err != nil {
+			return err
+		}
+
+		// A concurrent delete of another attachment with the same content
+		// may have removed the blob after it was stored above.
+		exists, err := s.blobs.Exists(ctx, key)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("attachment content was removed while uploading, try again")
+		}
+
+		id, err = tx.AddAttachment(ctx, attachment)
+		return err
+	})
+	if err != nil {
+		s.releaseBlob(ctx, key)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	created, err := s.store.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created, nil
+}
+
+// GetAttachments lists the attachments of a target. It requires clearance
+// for the mission's classification.
+func (s *AttachmentService) GetAttachments(ctx context.Context, missionID, targetID int64) ([]domain.Attachment, error) {
+	const op = "service.AttachmentService.GetAttachments"
+
+	if _, _, err := readableTarget(ctx, s.store, missionID, targetID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attachments, err := s.store.GetAttachments(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attachments, nil
+}
+
+// OpenAttachment returns an attachment together with its content, which
+// the caller must close. It requires clearance for the mission's
+// classification.
+func (s *AttachmentService) OpenAttachment(ctx context.Context, missionID, targetID, attachmentID int64) (*domain.Attachment, io.ReadCloser, error) {
+	const op = "service.AttachmentService.OpenAttachment"
+
+	if _, _, err := readableTarget(ctx, s.store, missionID, targetID); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attachment, err := getAttachment(ctx, s.store, targetID, attachmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	content, err := s.blobs.Open(ctx, attachment.SHA256)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attachment, content, nil
+}
+
+// DeleteAttachment removes an attachment from a target that is not frozen
+// yet. Its content is removed as well unless another attachment shares it.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, missionID, targetID, attachmentID int64) error {
+	const op = "service.AttachmentService.DeleteAttachment"
+
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		if err := checkAttachmentsWritable(ctx, tx, missionID, targetID); err != nil {
+			return err
+		}
+
+		attachment, err := getAttachment(ctx, tx, targetID, attachmentID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.DeleteAttachment(ctx, attachmentID); err != nil {
+			return err
+		}
+
+		// Removed while the transaction holds the write lock, so no other
+		// attachment can start referring to the blob in between.
+		inUse, err := tx.AttachmentBlobInUse(ctx, attachment.SHA256)
+		if err != nil || inUse {
+			return err
+		}
+		return s.blobs.Delete(ctx, attachment.SHA256)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PruneBlobs removes blobs that no attachment refers to, such as the
+// content of attachments whose target was deleted. Blobs stored less than
+// grace ago are kept, since their upload may still be being recorded. It
+// returns the number of blobs removed.
+func (s *AttachmentService) PruneBlobs(ctx context.Context, grace time.Duration) (int, error) {
+	const op = "service.AttachmentService.PruneBlobs"
+
+	blobs, err := s.blobs.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	cutoff := time.Now().Add(-grace)
+	pruned := 0
+	for _, blob := range blobs {
+		if blob.StoredAt.After(cutoff) {
+			continue
+		}
+
+		err := s.store.WithTx(ctx, func(tx storage.Store) error {
+			inUse, err := tx.AttachmentBlobInUse(ctx, blob.Key)
+			if err != nil || inUse {
+				return err
+			}
+			if err := s.blobs.Delete(ctx, blob.Key); err != nil {
+				return err
+			}
+			pruned++
+			return nil
+		})
+		if err != nil {
+			return pruned, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return pruned, nil
+}
+
+// releaseBlob removes a blob stored for an upload that could not be
+// recorded, unless an attachment already refers to the same content. The
+// upload has failed anyway, so errors are not reported; prune-blobs cleans
+// up whatever is left.
+func (s *AttachmentService) releaseBlob(ctx context.Context, key string) {
+	_ = s.store.WithTx(ctx, func(tx storage.Store) error {
+		inUse, err := tx.AttachmentBlobInUse(ctx, key)
+		if err != nil || inUse {
+			return err
+		}
+		return s.blobs.Delete(ctx, key)
+	})
+}
+
+func (s *AttachmentService) isAllowed(mediaType *mimetype.MIME) bool {
+	for _, allowed := range s.allowed {
+		if mediaType.Is(allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAttachmentsWritable fails unless attachments of the target may be
+// added or removed: the caller must be cleared for the mission, and
+// neither the target nor the mission may be complete.
+func checkAttachmentsWritable(ctx context.Context, store storage.Store, missionID, targetID int64) error {
+	mission, target, err := readableTarget(ctx, store, missionID, targetID)
+	if err != nil {
+		return err
+	}
+	if mission.Complete {
+		return domain.ErrMissionComplete
+	}
+	if target.Complete {
+		return domain.ErrTargetComplete
+	}
+	return nil
+}
+
+// getAttachment returns an attachment of the target. An attachment of
+// another target is reported as not found.
+func getAttachment(ctx context.Context, store storage.Store, targetID, attachmentID int64) (*domain.Attachment, error) {
+	attachment, err := store.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil || attachment.TargetID != targetID {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// cleanFilename keeps the base name of an uploaded file, without control
+// characters, so it can be returned safely in a Content-Disposition header.
+func cleanFilename(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
+
+// sizeLimitedReader fails with ErrAttachmentTooLarge once more than
+// remaining bytes have been read.
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, domain.ErrAttachmentTooLarge
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, domain.ErrAttachmentTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+// AuditService reads the audit log. Entries are written by the services
+// making the changes, in the same transaction as the change itself.
+type AuditService struct {
+	store storage.Store
+}
+
+func NewAuditService(store storage.Store) *AuditService {
+	return &AuditService{store: store}
+}
+
+// GetEntries returns a page of the audit log entries matching filter,
+// newest first, together with the total number of matching entries.
+func (s *AuditService) GetEntries(ctx context.Context, filter domain.AuditFilter, limit, offset int) ([]domain.AuditEntry, int, error) {
+	const op = "service.AuditService.GetEntries"
+
+	entries, total, err := s.store.GetAuditEntries(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, total, nil
+}
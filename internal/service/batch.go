@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+// MaxBatchItems bounds how many items a batch may have.
+const MaxBatchItems = 100
+
+// errBatchFailed rolls back an atomic batch that had a failing item.
+var errBatchFailed = errors.New("batch failed")
+
+// runBatch applies fn to each of n items, at most limit, in a single
+// transaction, each item in a savepoint of its own so a failing item leaves
+// no trace. Every item is tried even after one has failed, so the results
+// report all failures at once. In atomic mode the batch is rolled back when
+// any item failed and the items that succeeded are reported as
+// ErrBatchAborted, without the IDs they would have had.
+func runBatch(ctx context.Context, store storage.Store, mode domain.BatchMode, n, limit int, fn func(tx storage.Store, i int) (int64, error)) ([]domain.BatchResult, error) {
+	if n == 0 {
+		return nil, &domain.ValidationError{Err: errors.New("a batch must have at least one item")}
+	}
+	if n > limit {
+		return nil, &domain.ValidationError{Err: fmt.Errorf("a batch may have at most %d items", limit)}
+	}
+
+	results := make([]domain.BatchResult, n)
+	err := store.WithTx(ctx, func(tx storage.Store) error {
+		for i := range results {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var id int64
+			err := tx.WithTx(ctx, func(item storage.Store) error {
+				var err error
+				id, err = fn(item, i)
+				return err
+			})
+			results[i] = domain.BatchResult{Index: i, ID: id, Err: err}
+		}
+
+		if mode == domain.BatchAtomic && domain.BatchFailed(results) {
+			return errBatchFailed
+		}
+		return nil
+	})
+	if errors.Is(err, errBatchFailed) {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = domain.BatchResult{Index: i, Err: domain.ErrBatchAborted}
+			}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// CreateCats creates a batch of cats, each as CreateCat would.
+func (s *CatService) CreateCats(ctx context.Context, mode domain.BatchMode, cats []domain.SpyCat) ([]domain.BatchResult, error) {
+	const op = "service.CatService.CreateCats"
+
+	results, err := runBatch(ctx, s.store, mode, len(cats), MaxBatchItems, func(tx storage.Store, i int) (int64, error) {
+		cat := cats[i]
+		return (&CatService{store: tx, breeds: s.breeds}).CreateCat(ctx, cat.Name, cat.YearsOfExperience, cat.Breed, cat.Salary)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+// CreateMissions creates a batch of missions, each as CreateMission would.
+func (s *MissionService) CreateMissions(ctx context.Context, mode domain.BatchMode, missions []domain.Mission) ([]domain.BatchResult, error) {
+	const op = "service.MissionService.CreateMissions"
+
+	results, err := runBatch(ctx, s.store, mode, len(missions), MaxBatchItems, func(tx storage.Store, i int) (int64, error) {
+		return s.withStore(tx).CreateMission(ctx, missions[i])
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+// UpdateMissions completes, reopens or assigns a batch of missions, each as
+// UpdateMissionCompleteStatus or AssignCatToMission would.
+func (s *MissionService) UpdateMissions(ctx context.Context, mode domain.BatchMode, updates []domain.MissionUpdate) ([]domain.BatchResult, error) {
+	const op = "service.MissionService.UpdateMissions"
+
+	results, err := runBatch(ctx, s.store, mode, len(updates), MaxBatchItems, func(tx storage.Store, i int) (int64, error) {
+		update := updates[i]
+		switch {
+		case (update.Complete == nil) == (update.CatID == nil):
+			return update.ID, &domain.ValidationError{Err: errors.New("exactly one of complete and cat_id must be set")}
+		case update.Complete != nil:
+			return update.ID, s.withStore(tx).UpdateMissionCompleteStatus(ctx, update.ID, *update.Complete)
+		default:
+			return update.ID, s.withStore(tx).AssignCatToMission(ctx, update.ID, *update.CatID)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+// DeleteMissions deletes a batch of missions, none of which may be assigned
+// to a cat, as DeleteMission would.
+func (s *MissionService) DeleteMissions(ctx context.Context, mode domain.BatchMode, ids []int64) ([]domain.BatchResult, error) {
+	const op = "service.MissionService.DeleteMissions"
+
+	var results []domain.BatchResult
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		var err error
+		results, err = runBatch(ctx, tx, mode, len(ids), MaxBatchItems, func(tx storage.Store, i int) (int64, error) {
+			mission, err := getMission(ctx, tx, ids[i])
+			if err != nil {
+				return ids[i], err
+			}
+			if err := checkClearance(ctx, mission.Classification); err != nil {
+				return ids[i], err
+			}
+			if mission.CatID != nil {
+				return ids[i], domain.ErrMissionAssigned
+			}
+			return ids[i], nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// The missions are only checked above and deleted together here.
+		var deletable []int64
+		for _, r := range results {
+			if r.Err == nil {
+				deletable = append(deletable, r.ID)
+			}
+		}
+		return tx.DeleteUnassignedMission(ctx, deletable)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+// withStore returns a copy of the service that works on store, typically a
+// transaction the caller has opened.
+func (s *MissionService) withStore(store storage.Store) *MissionService {
+	return &MissionService{store: store, rules: s.rules}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/rules"
+)
+
+// missionBatch has a valid mission on either side of one with no targets.
+func missionBatch() []domain.Mission {
+	target := domain.Target{Name: "Courier", Country: "UA"}
+	return []domain.Mission{
+		{Targets: []domain.Target{target}},
+		{},
+		{Targets: []domain.Target{target}},
+	}
+}
+
+func TestCreateMissionsAtomic(t *testing.T) {
+	store := newTestStore(t)
+	missions := NewMissionService(store, newTestRules(t))
+	ctx := cleared(domain.ClassificationTopSecret)
+
+	results, err := missions.CreateMissions(ctx, domain.BatchAtomic, missionBatch())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+
+	var violation *rules.Violation
+	if !errors.As(results[1].Err, &violation) || violation.Rule != rules.RuleMinTargets {
+		t.Errorf("failing item: got %v, want a %s violation", results[1].Err, rules.RuleMinTargets)
+	}
+	for _, i := range []int{0, 2} {
+		if !errors.Is(results[i].Err, domain.ErrBatchAborted) || results[i].ID != 0 {
+			t.Errorf("item %d: got ID %d and %v, want %v", i, results[i].ID, results[i].Err, domain.ErrBatchAborted)
+		}
+	}
+
+	all, err := missions.GetAllMissions(ctx, domain.MissionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 0 {
+		t.Errorf("atomic batch with a failing item created %d missions", len(all))
+	}
+}
+
+func TestCreateMissionsPartial(t *testing.T) {
+	store := newTestStore(t)
+	missions := NewMissionService(store, newTestRules(t))
+	ctx := cleared(domain.ClassificationTopSecret)
+
+	results, err := missions.CreateMissions(ctx, domain.BatchPartial, missionBatch())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+
+	if results[1].Err == nil || errors.Is(results[1].Err, domain.ErrBatchAborted) {
+		t.Errorf("failing item: got %v, want its own error", results[1].Err)
+	}
+	for _, i := range []int{0, 2} {
+		if results[i].Err != nil {
+			t.Errorf("item %d: %v", i, results[i].Err)
+			continue
+		}
+		if _, err := missions.GetMission(ctx, results[i].ID); err != nil {
+			t.Errorf("item %d was not created: %v", i, err)
+		}
+	}
+
+	all, err := missions.GetAllMissions(ctx, domain.MissionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Errorf("partial batch created %d missions, want 2", len(all))
+	}
+}
+
+func TestCreateMissionsBatchSize(t *testing.T) {
+	missions := NewMissionService(newTestStore(t), newTestRules(t))
+	ctx := cleared(domain.ClassificationTopSecret)
+
+	for _, n := range []int{0, MaxBatchItems + 1} {
+		_, err := missions.CreateMissions(ctx, domain.BatchPartial, make([]domain.Mission, n))
+		var invalid *domain.ValidationError
+		if !errors.As(err, &invalid) {
+			t.Errorf("batch of %d: got %v, want a validation error", n, err)
+		}
+	}
+}
+
+func TestDeleteMissions(t *testing.T) {
+	modes := map[domain.BatchMode][]bool{
+		// Whether each mission is deleted: only the public, unassigned one
+		// may be, and an atomic batch deletes none when another fails.
+		domain.BatchAtomic:  {false, false, false},
+		domain.BatchPartial: {true, false, false},
+	}
+	for mode, deleted := range modes {
+		t.Run(string(mode), func(t *testing.T) {
+			store := newTestStore(t)
+			missions := NewMissionService(store, newTestRules(t))
+
+			catID, err := store.CreateCat(context.Background(), "Shadow", 5, "Bengal", "beng", 1000)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := []int64{
+				createMission(t, missions, domain.ClassificationPublic, 1),
+				createMission(t, missions, domain.ClassificationSecret, 1),
+				createMission(t, missions, domain.ClassificationPublic, 1),
+			}
+			if err := missions.AssignCatToMission(cleared(domain.ClassificationPublic), ids[2], catID); err != nil {
+				t.Fatal(err)
+			}
+
+			results, err := missions.DeleteMissions(cleared(domain.ClassificationConfidential), mode, ids)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != len(ids) {
+				t.Fatalf("got %d results, want %d", len(results), len(ids))
+			}
+
+			if !errors.Is(results[1].Err, domain.ErrInsufficientClearance) {
+				t.Errorf("secret mission: got %v, want %v", results[1].Err, domain.ErrInsufficientClearance)
+			}
+			if !errors.Is(results[2].Err, domain.ErrMissionAssigned) {
+				t.Errorf("assigned mission: got %v, want %v", results[2].Err, domain.ErrMissionAssigned)
+			}
+			want := error(nil)
+			if mode == domain.BatchAtomic {
+				want = domain.ErrBatchAborted
+			}
+			if !errors.Is(results[0].Err, want) {
+				t.Errorf("deletable mission: got %v, want %v", results[0].Err, want)
+			}
+
+			for i, id := range ids {
+				_, err := missions.GetMission(cleared(domain.ClassificationTopSecret), id)
+				if gone := errors.Is(err, domain.ErrMissionNotFound); gone != deleted[i] {
+					t.Errorf("mission %d: deleted = %v, want %v (%v)", i, gone, deleted[i], err)
+				}
+			}
+		})
+	}
+}
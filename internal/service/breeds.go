@@ -0,0 +1,54 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// suggestionLimit caps the "did you mean" suggestions for a rejected breed.
+const suggestionLimit = 3
+
+// BreedCatalog is the set of known breeds.
+type BreedCatalog interface {
+	Resolve(breed string) (domain.Breed, bool)
+	Breed(id string) (domain.Breed, bool)
+	Search(query string) []domain.Breed
+	Suggest(breed string, limit int) []domain.Breed
+}
+
+type BreedService struct {
+	catalog BreedCatalog
+}
+
+func NewBreedService(catalog BreedCatalog) *BreedService {
+	return &BreedService{catalog: catalog}
+}
+
+func (s *BreedService) SearchBreeds(query string) []domain.Breed {
+	return s.catalog.Search(query)
+}
+
+func (s *BreedService) GetBreed(id string) (*domain.Breed, error) {
+	const op = "service.BreedService.GetBreed"
+
+	breed, ok := s.catalog.Breed(id)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrBreedNotFound)
+	}
+	return &breed, nil
+}
+
+// resolveBreed accepts a breed ID or name in any case. Unknown breeds are
+// reported as a domain.BreedError listing the closest known names.
+func resolveBreed(catalog BreedCatalog, breed string) (domain.Breed, error) {
+	if resolved, ok := catalog.Resolve(breed); ok {
+		return resolved, nil
+	}
+
+	var suggestions []string
+	for _, b := range catalog.Suggest(breed, suggestionLimit) {
+		suggestions = append(suggestions, b.Name)
+	}
+	return domain.Breed{}, &domain.BreedError{Breed: breed, Suggestions: suggestions}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+type CatService struct {
+	store  storage.Store
+	breeds BreedCatalog
+}
+
+func NewCatService(store storage.Store, breeds BreedCatalog) *CatService {
+	return &CatService{store: store, breeds: breeds}
+}
+
+func (s *CatService) CreateCat(ctx context.Context, name string, yearsOfExperience int, breed string, salary float64) (int64, error) {
+	const op = "service.CatService.CreateCat"
+
+	cat := domain.SpyCat{
+		Name:              name,
+		YearsOfExperience: yearsOfExperience,
+		Breed:             breed,
+		Salary:            salary,
+	}
+	if err := validateStruct(cat); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	resolved, err := resolveBreed(s.breeds, breed)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.store.CreateCat(ctx, name, yearsOfExperience, resolved.Name, resolved.ID, salary)
+}
+
+func (s *CatService) GetCatByID(ctx context.Context, id int64) (*domain.SpyCat, error) {
+	const op = "service.CatService.GetCatByID"
+
+	cat, err := s.store.GetCatByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if cat == nil {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrCatNotFound)
+	}
+
+	return cat, nil
+}
+
+// GetCatProfile returns the cat with its full breed record. Cats whose breed
+// ID was never recorded are matched by breed name; if the breed is no longer
+// known only its name is returned.
+func (s *CatService) GetCatProfile(ctx context.Context, id int64) (*domain.SpyCatProfile, error) {
+	const op = "service.CatService.GetCatProfile"
+
+	cat, err := s.GetCatByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	breed, ok := s.breeds.Breed(cat.BreedID)
+	if !ok {
+		breed, ok = s.breeds.Resolve(cat.Breed)
+	}
+	if !ok {
+		breed = domain.Breed{ID: cat.BreedID, Name: cat.Breed}
+	}
+
+	return &domain.SpyCatProfile{SpyCat: *cat, Breed: &breed}, nil
+}
+
+// GetCatMissions returns a page of the cat's past and current missions, most
+// recently assigned first, and the total number of its missions. Target
+// notes of missions the caller is not cleared for are withheld.
+func (s *CatService) GetCatMissions(ctx context.Context, id int64, limit, offset int) ([]domain.Mission, int, error) {
+	const op = "service.CatService.GetCatMissions"
+
+	if _, err := s.GetCatByID(ctx, id); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	missions, total, err := s.store.GetMissionsForCat(ctx, id, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	redactMissions(ctx, missions)
+
+	return missions, total, nil
+}
+
+func (s *CatService) GetCatStats(ctx context.Context, id int64) (*domain.CatStats, error) {
+	const op = "service.CatService.GetCatStats"
+
+	if _, err := s.GetCatByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats, err := s.store.GetCatStats(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func (s *CatService) GetAllCats(ctx context.Context, filter domain.CatFilter) ([]domain.SpyCat, error) {
+	return s.store.GetAllCats(ctx, filter)
+}
+
+func (s *CatService) UpdateCatSalary(ctx context.Context, id int64, salary float64) error {
+	const op = "service.CatService.UpdateCatSalary"
+
+	if err := validateVar("salary", salary, "gt=0"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s.store.UpdateCatSalary(ctx, id, salary)
+}
+
+// DeleteCat deletes a cat together with the missions assigned to it, which
+// requires clearance for every one of those missions.
+func (s *CatService) DeleteCat(ctx context.Context, id int64) error {
+	const op = "service.CatService.DeleteCat"
+
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		classifications, err := tx.GetMissionClassificationsForCat(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkClearance(ctx, classifications...); err != nil {
+			return err
+		}
+
+		return tx.DeleteCat(ctx, id)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
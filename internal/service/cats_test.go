@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+func TestDeleteCatRequiresClearanceForItsMissions(t *testing.T) {
+	store := newTestStore(t)
+	missions := NewMissionService(store, newTestRules(t))
+	cats := NewCatService(store, nil)
+
+	catID, err := store.CreateCat(context.Background(), "Shadow", 5, "Bengal", "beng", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missionID := createMission(t, missions, domain.ClassificationSecret, 1)
+	if err := missions.AssignCatToMission(cleared(domain.ClassificationSecret), missionID, catID); err != nil {
+		t.Fatal(err)
+	}
+
+	err = cats.DeleteCat(cleared(domain.ClassificationConfidential), catID)
+	if !errors.Is(err, domain.ErrInsufficientClearance) {
+		t.Fatalf("delete without clearance: got %v, want %v", err, domain.ErrInsufficientClearance)
+	}
+	if _, err := cats.GetCatByID(context.Background(), catID); err != nil {
+		t.Errorf("cat was deleted without clearance: %v", err)
+	}
+	if _, err := missions.GetMission(context.Background(), missionID); err != nil {
+		t.Errorf("mission was deleted without clearance: %v", err)
+	}
+
+	if err := cats.DeleteCat(cleared(domain.ClassificationSecret), catID); err != nil {
+		t.Fatalf("delete with clearance: %v", err)
+	}
+	if _, err := missions.GetMission(context.Background(), missionID); !errors.Is(err, domain.ErrMissionNotFound) {
+		t.Errorf("mission after deleting its cat: got %v, want %v", err, domain.ErrMissionNotFound)
+	}
+}
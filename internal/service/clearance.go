@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+
+	"github.com/illiakornyk/spy-cat/internal/auth"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+// redactMissions withholds the target notes of every mission the principal
+// in ctx is not cleared to read.
+func redactMissions(ctx context.Context, missions []domain.Mission) {
+	clearance := auth.FromContext(ctx).Clearance
+	for i := range missions {
+		if !clearance.Covers(missions[i].Classification) {
+			missions[i].Redact()
+		}
+	}
+}
+
+// redactTargets withholds the notes and location of every target whose
+// mission the principal in ctx is not cleared to read.
+func redactTargets(ctx context.Context, store storage.Store, targets []domain.Target) error {
+	ids := make([]int64, len(targets))
+	for i, t := range targets {
+		ids[i] = t.MissionID
+	}
+	classifications, err := store.GetMissionClassifications(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	clearance := auth.FromContext(ctx).Clearance
+	for i := range targets {
+		if !clearance.Covers(classifications[targets[i].MissionID]) {
+			targets[i].Redact()
+		}
+	}
+	return nil
+}
+
+// checkClearance fails unless the principal in ctx is cleared for every
+// one of classifications.
+func checkClearance(ctx context.Context, classifications ...domain.Classification) error {
+	clearance := auth.FromContext(ctx).Clearance
+	for _, c := range classifications {
+		if !clearance.Covers(c) {
+			return domain.ErrInsufficientClearance
+		}
+	}
+	return nil
+}
+
+// setLevels fills in the default priority and classification of a new
+// mission and validates the given ones.
+func setLevels(mission *domain.Mission) error {
+	var err error
+	if mission.Priority == "" {
+		mission.Priority = domain.PriorityNormal
+	} else if mission.Priority, err = domain.ParsePriority(string(mission.Priority)); err != nil {
+		return err
+	}
+
+	if mission.Classification == "" {
+		mission.Classification = domain.ClassificationPublic
+	} else if mission.Classification, err = domain.ParseClassification(string(mission.Classification)); err != nil {
+		return err
+	}
+	return nil
+}
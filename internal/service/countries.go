@@ -0,0 +1,29 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/countries"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// normalizeCountry replaces the country of target, which may be a name or
+// a common alias, with its ISO 3166-1 alpha-2 code.
+func normalizeCountry(target *domain.Target) error {
+	code, err := countryCode(target.Country)
+	if err != nil {
+		return err
+	}
+	target.Country = code
+	return nil
+}
+
+// countryCode returns the ISO 3166-1 alpha-2 code of the country named by
+// name.
+func countryCode(name string) (string, error) {
+	c, ok := countries.Lookup(name)
+	if !ok {
+		return "", &domain.ValidationError{Err: fmt.Errorf("unknown country %q", name)}
+	}
+	return c.Alpha2, nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/illiakornyk/spy-cat/internal/auth"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/geo"
+)
+
+// FindNearby returns the targets within radiusKM of the point at lat and
+// lon, closest first, together with the total number found. Targets of
+// missions the caller is not cleared to read are left out.
+func (s *TargetService) FindNearby(ctx context.Context, lat, lon, radiusKM float64, limit, offset int) ([]domain.NearbyTarget, int, error) {
+	const op = "service.TargetService.FindNearby"
+
+	center := geo.Point{Lat: lat, Lon: lon}
+	if !center.Valid() {
+		return nil, 0, fmt.Errorf("%s: %w", op, &domain.ValidationError{Err: errors.New("lat must be between -90 and 90 and lon between -180 and 180")})
+	}
+	if radiusKM <= 0 || radiusKM > geo.MaxDistanceKM {
+		return nil, 0, fmt.Errorf("%s: %w", op, &domain.ValidationError{Err: fmt.Errorf("radius_km must be greater than 0 and at most %.0f", geo.MaxDistanceKM)})
+	}
+
+	box := geo.BoundingBox(center, radiusKM)
+	candidates, err := s.store.GetTargetsInArea(ctx, domain.TargetArea{
+		MinLat:          box.MinLat,
+		MaxLat:          box.MaxLat,
+		MinLon:          box.MinLon,
+		MaxLon:          box.MaxLon,
+		Classifications: readableClassifications(ctx),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The box is only a prefilter: its corners are further away than the
+	// radius.
+	var nearby []domain.NearbyTarget
+	for _, c := range candidates {
+		c.DistanceKM = geo.DistanceKM(center, geo.Point{Lat: *c.Target.Latitude, Lon: *c.Target.Longitude})
+		if c.DistanceKM <= radiusKM {
+			c.DistanceKM = math.Round(c.DistanceKM*1000) / 1000
+			nearby = append(nearby, c)
+		}
+	}
+	sort.SliceStable(nearby, func(i, j int) bool {
+		if nearby[i].DistanceKM != nearby[j].DistanceKM {
+			return nearby[i].DistanceKM < nearby[j].DistanceKM
+		}
+		return nearby[i].Target.ID < nearby[j].Target.ID
+	})
+
+	total := len(nearby)
+	if offset >= total {
+		return nil, total, nil
+	}
+	nearby = nearby[offset:]
+	if len(nearby) > limit {
+		nearby = nearby[:limit]
+	}
+
+	return nearby, total, nil
+}
+
+// checkLocation fails unless the latitude and longitude of target are
+// either both set or both unset.
+func checkLocation(target domain.Target) error {
+	if (target.Latitude == nil) != (target.Longitude == nil) {
+		return &domain.ValidationError{Err: errors.New("latitude and longitude must be set together")}
+	}
+	return nil
+}
+
+// readableClassifications lists the classifications the principal in ctx
+// is cleared for.
+func readableClassifications(ctx context.Context) []domain.Classification {
+	clearance := auth.FromContext(ctx).Clearance
+
+	var readable []domain.Classification
+	for _, c := range domain.Classifications {
+		if clearance.Covers(c) {
+			readable = append(readable, c)
+		}
+	}
+	return readable
+}
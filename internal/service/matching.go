@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/matching"
+	"github.com/illiakornyk/spy-cat/internal/rules"
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+// MatchingService recommends cats for unassigned missions.
+type MatchingService struct {
+	store  storage.Store
+	rules  *rules.Engine
+	scorer *matching.Scorer
+	breeds BreedCatalog
+}
+
+func NewMatchingService(store storage.Store, rules *rules.Engine, scorer *matching.Scorer, breeds BreedCatalog) *MatchingService {
+	return &MatchingService{store: store, rules: rules, scorer: scorer, breeds: breeds}
+}
+
+// GetCandidates ranks the cats that could take the mission, best first.
+// Cats that the rules would not allow on the mission are left out. A
+// positive limit caps the number of candidates returned.
+func (s *MatchingService) GetCandidates(ctx context.Context, missionID int64, limit int) ([]matching.Candidate, error) {
+	const op = "service.MatchingService.GetCandidates"
+
+	var candidates []matching.Candidate
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		mission, err := openMission(ctx, tx, missionID)
+		if err != nil {
+			return err
+		}
+
+		candidates, err = s.rank(ctx, tx, mission)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+// AutoAssign assigns the best ranked candidate to the mission.
+func (s *MatchingService) AutoAssign(ctx context.Context, missionID int64) (*matching.Candidate, error) {
+	const op = "service.MatchingService.AutoAssign"
+
+	var chosen matching.Candidate
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		mission, err := openMission(ctx, tx, missionID)
+		if err != nil {
+			return err
+		}
+		if err := checkClearance(ctx, mission.Classification); err != nil {
+			return err
+		}
+
+		candidates, err := s.rank(ctx, tx, mission)
+		if err != nil {
+			return err
+		}
+		if len(candidates) == 0 {
+			return domain.ErrNoCandidates
+		}
+
+		chosen = candidates[0]
+		return tx.AssignCatToMission(ctx, missionID, chosen.Cat.ID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &chosen, nil
+}
+
+func (s *MatchingService) rank(ctx context.Context, store storage.Store, mission *domain.Mission) ([]matching.Candidate, error) {
+	cats, err := store.GetAllCats(ctx, domain.CatFilter{})
+	if err != nil {
+		return nil, err
+	}
+	records, err := store.GetCatTrackRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recordByCat := make(map[int64]domain.CatTrackRecord, len(records))
+	for _, r := range records {
+		recordByCat[r.CatID] = r
+	}
+
+	countries := targetCountries(mission.Targets)
+	var profiles []matching.CatProfile
+	for _, cat := range cats {
+		record := recordByCat[cat.ID]
+		if s.rules.CheckCatAssignment(record.ActiveMissions) != nil ||
+			s.rules.CheckCountryExperience(cat.YearsOfExperience, countries) != nil {
+			continue
+		}
+
+		profile := matching.CatProfile{Cat: cat, Record: record}
+		if breed, ok := s.breeds.Breed(cat.BreedID); ok {
+			profile.Breed = &breed
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return s.scorer.Rank(countries, profiles), nil
+}
+
+// openMission returns a mission that is neither complete nor assigned.
+func openMission(ctx context.Context, store storage.Store, id int64) (*domain.Mission, error) {
+	mission, err := getMission(ctx, store, id)
+	if err != nil {
+		return nil, err
+	}
+	if mission.Complete {
+		return nil, domain.ErrMissionComplete
+	}
+	if mission.CatID != nil {
+		return nil, domain.ErrMissionAssigned
+	}
+	return mission, nil
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/rules"
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+// MissionService manages missions and the assignment of cats to them.
+type MissionService struct {
+	store storage.Store
+	rules *rules.Engine
+}
+
+func NewMissionService(store storage.Store, rules *rules.Engine) *MissionService {
+	return &MissionService{store: store, rules: rules}
+}
+
+// CreateMission creates mission with its targets. Only the cat, completion
+// state, schedule, priority, classification and targets of mission are
+// used. Missions are normal priority and public unless set otherwise, and
+// the caller may not classify a mission above their own clearance.
+func (s *MissionService) CreateMission(ctx context.Context, mission domain.Mission) (int64, error) {
+	const op = "service.MissionService.CreateMission"
+
+	if err := setLevels(&mission); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkClearance(ctx, mission.Classification); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range mission.Targets {
+		if err := validateStruct(mission.Targets[i]); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := checkLocation(mission.Targets[i]); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := normalizeCountry(&mission.Targets[i]); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := checkSchedule(&mission); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.rules.CheckTargetCount(len(mission.Targets)); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int64
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		if mission.CatID != nil {
+			if err := s.checkCatCanTakeMission(ctx, tx, *mission.CatID, targetCountries(mission.Targets)); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		id, err = tx.CreateMission(ctx, mission)
+		if err != nil {
+			return err
+		}
+
+		created, err := getMission(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		for _, t := range created.Targets {
+			if err := recordNotes(ctx, tx, t.ID, t.Notes); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetMission returns the mission, withholding the target notes when the
+// caller is not cleared for its classification.
+func (s *MissionService) GetMission(ctx context.Context, id int64) (*domain.Mission, error) {
+	const op = "service.MissionService.GetMission"
+
+	mission, err := getMission(ctx, s.store, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if checkClearance(ctx, mission.Classification) != nil {
+		mission.Redact()
+	}
+
+	return mission, nil
+}
+
+// GetAllMissions lists the missions matching filter, withholding the target
+// notes of those the caller is not cleared for.
+func (s *MissionService) GetAllMissions(ctx context.Context, filter domain.MissionFilter) ([]domain.Mission, error) {
+	const op = "service.MissionService.GetAllMissions"
+
+	missions, err := s.store.GetAllMissions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	redactMissions(ctx, missions)
+	return missions, nil
+}
+
+// UpdateMissionLevels changes the priority and classification of a
+// mission; nil leaves a value unchanged. Reclassifying requires clearance
+// for both the current and the new classification, so a mission cannot be
+// downgraded by someone who may not read it.
+func (s *MissionService) UpdateMissionLevels(ctx context.Context, id int64, priority *domain.Priority, classification *domain.Classification) error {
+	const op = "service.MissionService.UpdateMissionLevels"
+
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		mission, err := getMission(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		newPriority := mission.Priority
+		if priority != nil {
+			if newPriority, err = domain.ParsePriority(string(*priority)); err != nil {
+				return err
+			}
+		}
+
+		newClassification := mission.Classification
+		if classification != nil {
+			if newClassification, err = domain.ParseClassification(string(*classification)); err != nil {
+				return err
+			}
+		}
+
+		if err := checkClearance(ctx, mission.Classification, newClassification); err != nil {
+			return err
+		}
+
+		return tx.UpdateMissionLevels(ctx, id, newPriority, newClassification)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *MissionService) UpdateMissionCompleteStatus(ctx context.Context, id int64, complete bool) error {
+	const op = "service.MissionService.UpdateMissionCompleteStatus"
+
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		mission, err := getMission(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkClearance(ctx, mission.Classification); err != nil {
+			return err
+		}
+
+		if complete {
+			allComplete, err := tx.AreAllTargetsComplete(ctx, id)
+			if err != nil {
+				return err
+			}
+			if !allComplete {
+				return domain.ErrIncompleteTargets
+			}
+		}
+
+		return tx.UpdateMissionCompleteStatus(ctx, id, complete)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *MissionService) AssignCatToMission(ctx context.Context, missionID, catID int64) error {
+	const op = "service.MissionService.AssignCatToMission"
+
+	if err := validateVar("cat_id", catID, "required,min=1"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		mission, err := getMission(ctx, tx, missionID)
+		if err != nil {
+			return err
+		}
+		if err := checkClearance(ctx, mission.Classification); err != nil {
+			return err
+		}
+		if mission.Complete {
+			return domain.ErrMissionComplete
+		}
+
+		if err := s.checkCatCanTakeMission(ctx, tx, catID, targetCountries(mission.Targets)); err != nil {
+			return err
+		}
+
+		return tx.AssignCatToMission(ctx, missionID, catID)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteMission deletes a mission that has not been assigned to a cat yet.
+func (s *MissionService) DeleteMission(ctx context.Context, id int64) error {
+	const op = "service.MissionService.DeleteMission"
+
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		mission, err := getMission(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkClearance(ctx, mission.Classification); err != nil {
+			return err
+		}
+
+		return tx.DeleteUnassignedMission(ctx, []int64{id})
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// checkCatCanTakeMission applies the per-cat rules for a mission with
+// targets in countries.
+func (s *MissionService) checkCatCanTakeMission(ctx context.Context, store storage.Store, catID int64, countries []string) error {
+	cat, err := store.GetCatByID(ctx, catID)
+	if err != nil {
+		return err
+	}
+	if cat == nil {
+		return domain.ErrCatNotFound
+	}
+
+	active, err := store.CountActiveMissionsForCat(ctx, catID)
+	if err != nil {
+		return err
+	}
+	if err := s.rules.CheckCatAssignment(active); err != nil {
+		return err
+	}
+
+	return s.rules.CheckCountryExperience(cat.YearsOfExperience, countries)
+}
+
+func getMission(ctx context.Context, store storage.Store, id int64) (*domain.Mission, error) {
+	mission, err := store.GetMission(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if mission == nil {
+		return nil, domain.ErrMissionNotFound
+	}
+	return mission, nil
+}
+
+// checkSchedule verifies that a mission starts before it is due and that
+// every target deadline falls within the mission window.
+func checkSchedule(mission *domain.Mission) error {
+	if mission.StartsAt != nil && mission.DueAt != nil && !mission.DueAt.After(*mission.StartsAt) {
+		return &domain.ValidationError{Err: errors.New("due_at must be after starts_at")}
+	}
+
+	for _, t := range mission.Targets {
+		if err := checkTargetDeadline(mission, t.DueAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTargetDeadline verifies that a target deadline falls within the
+// window of its mission.
+func checkTargetDeadline(mission *domain.Mission, due *time.Time) error {
+	if due == nil {
+		return nil
+	}
+	if mission.StartsAt != nil && due.Before(*mission.StartsAt) {
+		return &domain.ValidationError{Err: errors.New("target due_at must not be before the mission starts_at")}
+	}
+	if mission.DueAt != nil && due.After(*mission.DueAt) {
+		return &domain.ValidationError{Err: errors.New("target due_at must not be after the mission due_at")}
+	}
+	return nil
+}
+
+func targetCountries(targets []domain.Target) []string {
+	countries := make([]string, 0, len(targets))
+	for _, t := range targets {
+		countries = append(countries, t.Country)
+	}
+	return countries
+}
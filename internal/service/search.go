@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/illiakornyk/spy-cat/internal/countries"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+// maxSearchTerms bounds how many words and phrases a search may have.
+const maxSearchTerms = 16
+
+// SearchService finds cats, missions and targets by the words in their
+// names, breeds, countries and notes.
+type SearchService struct {
+	store storage.Store
+}
+
+func NewSearchService(store storage.Store) *SearchService {
+	return &SearchService{store: store}
+}
+
+// Search returns a page of the cats, missions and targets matching q, best matches
+// first, together with the total number of matches. q is a list of words,
+// all of which must match; text in double quotes matches as a phrase and a
+// word ending in * matches any word it starts. The notes of targets are
+// only searched in missions the caller is cleared for.
+func (s *SearchService) Search(ctx context.Context, q string, limit, offset int) ([]domain.SearchHit, int, error) {
+	const op = "service.SearchService.Search"
+
+	if err := validateVar("q", q, "max=200"); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	terms, err := parseSearch(q)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hits, total, err := s.store.Search(ctx, domain.SearchQuery{
+		Terms:           terms,
+		Classifications: readableClassifications(ctx),
+	}, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hits, total, nil
+}
+
+// parseSearch splits q into words and quoted phrases. Words are reduced to
+// their letters and digits, as the index only holds those.
+func parseSearch(q string) ([]domain.SearchTerm, error) {
+	// A country name of several words, such as United Kingdom, is kept
+	// together so it can match targets by their country code.
+	if _, ok := countries.Lookup(q); ok && !strings.ContainsAny(q, `"*`) {
+		if phrase := strings.Join(strings.FieldsFunc(q, isSeparator), " "); strings.Contains(phrase, " ") {
+			return []domain.SearchTerm{searchTerm(phrase, false)}, nil
+		}
+	}
+
+	var terms []domain.SearchTerm
+	for i, part := range strings.Split(q, `"`) {
+		// Every other part was enclosed in quotes.
+		if i%2 == 1 {
+			if phrase := strings.Join(strings.FieldsFunc(part, isSeparator), " "); phrase != "" {
+				terms = append(terms, searchTerm(phrase, false))
+			}
+			continue
+		}
+
+		for _, word := range strings.Fields(part) {
+			prefix := strings.HasSuffix(word, "*")
+			if word = strings.Join(strings.FieldsFunc(word, isSeparator), " "); word != "" {
+				terms = append(terms, searchTerm(word, prefix))
+			}
+		}
+	}
+
+	if len(terms) == 0 {
+		return nil, &domain.ValidationError{Err: errors.New("q must contain at least one word")}
+	}
+	if len(terms) > maxSearchTerms {
+		return nil, &domain.ValidationError{Err: fmt.Errorf("q may contain at most %d words and phrases", maxSearchTerms)}
+	}
+	return terms, nil
+}
+
+// searchTerm returns the term for text. Targets store countries as codes,
+// so a term naming a country carries the code to match them by.
+func searchTerm(text string, prefix bool) domain.SearchTerm {
+	term := domain.SearchTerm{Text: text, Prefix: prefix}
+	if country, ok := countries.Lookup(text); ok && !prefix &&
+		!strings.EqualFold(text, country.Alpha2) && !strings.EqualFold(text, country.Alpha3) {
+		term.Country = country.Alpha2
+	}
+	return term
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
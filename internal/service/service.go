@@ -0,0 +1,43 @@
+// Package service owns the business logic of the application. Handlers
+// (and any other transport) call into the services, which validate input,
+// apply the configured rules and only then touch storage.
+package service
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+var validate = validator.New()
+
+func validateStruct(v any) error {
+	if err := validate.Struct(v); err != nil {
+		return &domain.ValidationError{Err: err}
+	}
+	return nil
+}
+
+// validateVar checks a single value against tag. field names the value in
+// the error, since the validator cannot infer it.
+func validateVar(field string, v any, tag string) error {
+	if err := validate.Var(v, tag); err != nil {
+		return &domain.ValidationError{Err: fmt.Errorf("field validation for '%s' failed on the '%s' tag", field, tag)}
+	}
+	return nil
+}
+
+// Services bundles every service so a transport can be wired up in one go.
+type Services struct {
+	Breeds   *BreedService
+	Cats     *CatService
+	Missions *MissionService
+	Matching *MatchingService
+	Targets  *TargetService
+
+	Attachments *AttachmentService
+	Audit       *AuditService
+	Search      *SearchService
+	Transfer    *TransferService
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/auth"
+	"github.com/illiakornyk/spy-cat/internal/config"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/rules"
+	"github.com/illiakornyk/spy-cat/internal/storage/sqlite"
+)
+
+// newTestStore opens a fresh, fully migrated database.
+func newTestStore(t *testing.T) *sqlite.Storage {
+	t.Helper()
+
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "spy-cat.db"), sqlite.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+// newTestRules allows one to three targets per mission.
+func newTestRules(t *testing.T) *rules.Engine {
+	t.Helper()
+
+	minTargets, maxTargets := 1, 3
+	engine, err := rules.New(config.Rules{MinTargets: &minTargets, MaxTargets: &maxTargets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return engine
+}
+
+// cleared returns a context whose caller is cleared for clearance.
+func cleared(clearance domain.Classification) context.Context {
+	return auth.NewContext(context.Background(), auth.Principal{Name: "test", Clearance: clearance})
+}
+
+// createMission creates an unassigned mission with the given number of
+// targets, each with notes, and returns its ID.
+func createMission(t *testing.T, missions *MissionService, classification domain.Classification, targets int) int64 {
+	t.Helper()
+
+	mission := domain.Mission{Classification: classification}
+	for i := range targets {
+		mission.Targets = append(mission.Targets, domain.Target{
+			Name:    fmt.Sprintf("Target %d", i+1),
+			Country: "UA",
+			Notes:   fmt.Sprintf("notes of target %d", i+1),
+		})
+	}
+
+	id, err := missions.CreateMission(cleared(domain.ClassificationTopSecret), mission)
+	if err != nil {
+		t.Fatalf("CreateMission: %v", err)
+	}
+	return id
+}
@@ -0,0 +1,502 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/auth"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/rules"
+	"github.com/illiakornyk/spy-cat/internal/storage"
+	"github.com/illiakornyk/spy-cat/internal/textdiff"
+)
+
+// TargetService manages the targets of a mission.
+type TargetService struct {
+	store storage.Store
+	rules *rules.Engine
+}
+
+func NewTargetService(store storage.Store, rules *rules.Engine) *TargetService {
+	return &TargetService{store: store, rules: rules}
+}
+
+// AddTarget adds a target to the mission. Only the name, country, notes and
+// deadline of target are used. Like every change to a mission's targets, it
+// requires clearance for the mission's classification.
+func (s *TargetService) AddTarget(ctx context.Context, missionID int64, target domain.Target) (int64, error) {
+	const op = "service.TargetService.AddTarget"
+
+	if err := validateStruct(target); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkLocation(target); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := normalizeCountry(&target); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int64
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		mission, err := getMission(ctx, tx, missionID)
+		if err != nil {
+			return err
+		}
+		if err := checkClearance(ctx, mission.Classification); err != nil {
+			return err
+		}
+		if mission.Complete {
+			return domain.ErrMissionComplete
+		}
+
+		if err := s.rules.CheckCanAddTarget(len(mission.Targets)); err != nil {
+			return err
+		}
+
+		if err := checkTargetDeadline(mission, target.DueAt); err != nil {
+			return err
+		}
+
+		if mission.CatID != nil {
+			cat, err := tx.GetCatByID(ctx, *mission.CatID)
+			if err != nil {
+				return err
+			}
+			if cat != nil {
+				if err := s.rules.CheckCountryExperience(cat.YearsOfExperience, []string{target.Country}); err != nil {
+					return err
+				}
+			}
+		}
+
+		id, err = tx.AddTarget(ctx, missionID, target)
+		if err != nil {
+			return err
+		}
+
+		return recordNotes(ctx, tx, id, target.Notes)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetMissionTargets returns the targets of a mission, withholding their
+// notes and locations when the caller is not cleared for the mission.
+func (s *TargetService) GetMissionTargets(ctx context.Context, missionID int64) ([]domain.Target, error) {
+	const op = "service.TargetService.GetMissionTargets"
+
+	mission, err := getMission(ctx, s.store, missionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	redactMissions(ctx, []domain.Mission{*mission})
+
+	return mission.Targets, nil
+}
+
+// GetTarget returns a target of a mission, withholding its notes and
+// location when the caller is not cleared for the mission.
+func (s *TargetService) GetTarget(ctx context.Context, missionID, targetID int64) (*domain.Target, error) {
+	const op = "service.TargetService.GetTarget"
+
+	mission, target, err := getMissionTarget(ctx, s.store, missionID, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if checkClearance(ctx, mission.Classification) != nil {
+		target.Redact()
+	}
+
+	return target, nil
+}
+
+// GetTargets returns a page of the targets matching filter across all
+// missions, together with the total number of matching targets. Countries
+// may be given in any form countries.Lookup accepts.
+func (s *TargetService) GetTargets(ctx context.Context, filter domain.TargetFilter, limit, offset int) ([]domain.Target, int, error) {
+	const op = "service.TargetService.GetTargets"
+
+	for i, name := range filter.Countries {
+		code, err := countryCode(name)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		filter.Countries[i] = code
+	}
+
+	targets, total, err := s.store.GetTargets(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := redactTargets(ctx, s.store, targets); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return targets, total, nil
+}
+
+// UpdateTarget changes the details of a target that is not complete yet.
+// Coordinates can only be changed together.
+func (s *TargetService) UpdateTarget(ctx context.Context, missionID, targetID int64, update domain.TargetUpdate) error {
+	const op = "service.TargetService.UpdateTarget"
+
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		mission, target, err := readableTarget(ctx, tx, missionID, targetID)
+		if err != nil {
+			return err
+		}
+		if mission.Complete {
+			return domain.ErrMissionComplete
+		}
+		if target.Complete {
+			return domain.ErrTargetComplete
+		}
+
+		if (update.Latitude == nil) != (update.Longitude == nil) {
+			return &domain.ValidationError{Err: errors.New("latitude and longitude must be changed together")}
+		}
+
+		if update.Name != nil {
+			target.Name = *update.Name
+		}
+		if update.Country != nil {
+			target.Country = *update.Country
+		}
+		if update.Address != nil {
+			target.Address = *update.Address
+		}
+		if update.Latitude != nil {
+			target.Latitude, target.Longitude = update.Latitude, update.Longitude
+		}
+		if err := validateStruct(target); err != nil {
+			return err
+		}
+		if err := normalizeCountry(target); err != nil {
+			return err
+		}
+
+		if update.Country != nil && mission.CatID != nil {
+			cat, err := tx.GetCatByID(ctx, *mission.CatID)
+			if err != nil {
+				return err
+			}
+			if cat != nil {
+				if err := s.rules.CheckCountryExperience(cat.YearsOfExperience, []string{target.Country}); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.UpdateTarget(ctx, targetID, *target)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *TargetService) UpdateNotes(ctx context.Context, missionID, targetID int64, notes string) error {
+	const op = "service.TargetService.UpdateNotes"
+
+	if err := validateVar("notes", notes, "max=500"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		mission, target, err := readableTarget(ctx, tx, missionID, targetID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.rules.CheckNotesEditable(target.Complete, mission.Complete); err != nil {
+			return err
+		}
+
+		if err := tx.UpdateNotes(ctx, targetID, notes); err != nil {
+			return err
+		}
+
+		_, err = tx.AddNoteRevision(ctx, targetID, domain.NoteRevisionNotes, notes, auth.FromContext(ctx).Name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AddObservation appends an observation to the notes history of a target
+// without replacing its notes, and returns its revision number. Like notes,
+// observations can no longer be added once the target is frozen.
+func (s *TargetService) AddObservation(ctx context.Context, missionID, targetID int64, text string) (int, error) {
+	const op = "service.TargetService.AddObservation"
+
+	if err := validateVar("text", text, "required,max=500"); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var revision int
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		mission, target, err := readableTarget(ctx, tx, missionID, targetID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.rules.CheckNotesEditable(target.Complete, mission.Complete); err != nil {
+			return err
+		}
+
+		revision, err = tx.AddNoteRevision(ctx, targetID, domain.NoteRevisionObservation, text, auth.FromContext(ctx).Name)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revision, nil
+}
+
+// GetNoteHistory returns every revision of a target's notes, oldest first.
+// It requires clearance for the mission's classification.
+func (s *TargetService) GetNoteHistory(ctx context.Context, missionID, targetID int64) ([]domain.NoteRevision, error) {
+	const op = "service.TargetService.GetNoteHistory"
+
+	revisions, err := s.noteHistory(ctx, missionID, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revisions, nil
+}
+
+// DiffNotes compares a target's notes as they stood at revision from with
+// the notes at revision to. Observations do not change the notes, so at an
+// observation the notes are those of the latest earlier notes revision.
+func (s *TargetService) DiffNotes(ctx context.Context, missionID, targetID int64, from, to int) (*domain.NoteDiff, error) {
+	const op = "service.TargetService.DiffNotes"
+
+	revisions, err := s.noteHistory(ctx, missionID, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	before, err := notesAt(revisions, from)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	after, err := notesAt(revisions, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &domain.NoteDiff{From: from, To: to, Lines: textdiff.Lines(before, after)}, nil
+}
+
+func (s *TargetService) noteHistory(ctx context.Context, missionID, targetID int64) ([]domain.NoteRevision, error) {
+	mission, _, err := getMissionTarget(ctx, s.store, missionID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkClearance(ctx, mission.Classification); err != nil {
+		return nil, err
+	}
+
+	return s.store.GetNoteRevisions(ctx, targetID)
+}
+
+// notesAt returns the notes as they stood at revision.
+func notesAt(revisions []domain.NoteRevision, revision int) (string, error) {
+	if revision < 1 || revision > len(revisions) {
+		return "", domain.ErrRevisionNotFound
+	}
+
+	for i := revision - 1; i >= 0; i-- {
+		if revisions[i].Kind == domain.NoteRevisionNotes {
+			return revisions[i].Text, nil
+		}
+	}
+	return "", nil
+}
+
+// recordNotes records the notes a target was created with as its first
+// revision.
+func recordNotes(ctx context.Context, store storage.Store, targetID int64, notes string) error {
+	if notes == "" {
+		return nil
+	}
+
+	_, err := store.AddNoteRevision(ctx, targetID, domain.NoteRevisionNotes, notes, auth.FromContext(ctx).Name)
+	return err
+}
+
+func (s *TargetService) UpdateCompleteStatus(ctx context.Context, missionID, targetID int64, complete bool) error {
+	const op = "service.TargetService.UpdateCompleteStatus"
+
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		if _, _, err := readableTarget(ctx, tx, missionID, targetID); err != nil {
+			return err
+		}
+
+		return tx.UpdateCompleteStatus(ctx, targetID, complete)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteTarget removes a target that has not been completed yet, unless
+// its mission would be left with fewer targets than the rules require.
+func (s *TargetService) DeleteTarget(ctx context.Context, missionID, targetID int64) error {
+	const op = "service.TargetService.DeleteTarget"
+
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		mission, target, err := readableTarget(ctx, tx, missionID, targetID)
+		if err != nil {
+			return err
+		}
+		if target.Complete {
+			return domain.ErrTargetComplete
+		}
+		if err := s.rules.CheckCanRemoveTarget(len(mission.Targets)); err != nil {
+			return err
+		}
+
+		return tx.DeleteTarget(ctx, targetID)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MoveTarget transfers an incomplete target, with its notes, history and
+// attachments, from one incomplete mission to another, and records the move
+// in the audit log. The source mission must keep its minimum number of
+// targets and the destination must have room for one more, and may not be
+// classified lower than the source. The caller must be cleared for both
+// missions.
+func (s *TargetService) MoveTarget(ctx context.Context, missionID, targetID, toMissionID int64) (*domain.Target, error) {
+	const op = "service.TargetService.MoveTarget"
+
+	if toMissionID == missionID {
+		return nil, fmt.Errorf("%s: %w", op, &domain.ValidationError{Err: errors.New("target already belongs to the mission")})
+	}
+
+	var moved *domain.Target
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		from, target, err := getMissionTarget(ctx, tx, missionID, targetID)
+		if err != nil {
+			return err
+		}
+		to, err := getMission(ctx, tx, toMissionID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkClearance(ctx, from.Classification, to.Classification); err != nil {
+			return err
+		}
+		// The notes, history and location of the target would otherwise
+		// become readable to everyone cleared for the lower classification.
+		if !to.Classification.Covers(from.Classification) {
+			return domain.ErrDeclassification
+		}
+		if from.Complete || to.Complete {
+			return domain.ErrMissionComplete
+		}
+		if target.Complete {
+			return domain.ErrTargetComplete
+		}
+
+		if err := s.rules.CheckCanRemoveTarget(len(from.Targets)); err != nil {
+			return err
+		}
+		if err := s.rules.CheckCanAddTarget(len(to.Targets)); err != nil {
+			return err
+		}
+		if err := checkTargetDeadline(to, target.DueAt); err != nil {
+			return err
+		}
+
+		if to.CatID != nil {
+			cat, err := tx.GetCatByID(ctx, *to.CatID)
+			if err != nil {
+				return err
+			}
+			if cat != nil {
+				if err := s.rules.CheckCountryExperience(cat.YearsOfExperience, []string{target.Country}); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.MoveTarget(ctx, targetID, toMissionID); err != nil {
+			return err
+		}
+
+		_, err = tx.AddAuditEntry(ctx, domain.AuditEntry{
+			Action:    domain.AuditTargetMoved,
+			Actor:     auth.FromContext(ctx).Name,
+			MissionID: &toMissionID,
+			TargetID:  &targetID,
+			Details:   map[string]any{"from_mission_id": missionID, "to_mission_id": toMissionID},
+		})
+		if err != nil {
+			return err
+		}
+
+		moved, err = tx.GetTarget(ctx, targetID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return moved, nil
+}
+
+// getMissionTarget returns a mission and one of its targets. A target of
+// another mission is reported as not found, so it can never be read or
+// changed through the wrong mission.
+func getMissionTarget(ctx context.Context, store storage.Store, missionID, targetID int64) (*domain.Mission, *domain.Target, error) {
+	mission, err := getMission(ctx, store, missionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target, err := store.GetTarget(ctx, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target == nil || target.MissionID != missionID {
+		return nil, nil, domain.ErrTargetNotFound
+	}
+
+	return mission, target, nil
+}
+
+// readableTarget returns a target, and its mission, that the caller is
+// cleared to read. Reading is required to change a target too.
+func readableTarget(ctx context.Context, store storage.Store, missionID, targetID int64) (*domain.Mission, *domain.Target, error) {
+	mission, target, err := getMissionTarget(ctx, store, missionID, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkClearance(ctx, mission.Classification); err != nil {
+		return nil, nil, err
+	}
+	return mission, target, nil
+}
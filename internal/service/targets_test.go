@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/rules"
+)
+
+func TestDeleteTargetKeepsMinimum(t *testing.T) {
+	store := newTestStore(t)
+	engine := newTestRules(t)
+	missions := NewMissionService(store, engine)
+	targets := NewTargetService(store, engine)
+
+	ctx := context.Background()
+
+	missionID := createMission(t, missions, domain.ClassificationPublic, 2)
+	mission, err := missions.GetMission(ctx, missionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := targets.DeleteTarget(ctx, missionID, mission.Targets[0].ID); err != nil {
+		t.Fatalf("delete down to the minimum: %v", err)
+	}
+
+	err = targets.DeleteTarget(ctx, missionID, mission.Targets[1].ID)
+	var violation *rules.Violation
+	if !errors.As(err, &violation) || violation.Rule != rules.RuleMinTargets {
+		t.Fatalf("delete below the minimum: got %v, want a %s violation", err, rules.RuleMinTargets)
+	}
+
+	mission, err = missions.GetMission(ctx, missionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mission.Targets) != 1 {
+		t.Errorf("mission has %d targets, want 1", len(mission.Targets))
+	}
+}
+
+func TestTargetChangesRequireClearance(t *testing.T) {
+	store := newTestStore(t)
+	engine := newTestRules(t)
+	missions := NewMissionService(store, engine)
+	targets := NewTargetService(store, engine)
+
+	missionID := createMission(t, missions, domain.ClassificationSecret, 2)
+	mission, err := missions.GetMission(cleared(domain.ClassificationSecret), missionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetID := mission.Targets[0].ID
+
+	ctx := cleared(domain.ClassificationConfidential)
+	changes := map[string]func() error{
+		"add": func() error {
+			_, err := targets.AddTarget(ctx, missionID, domain.Target{Name: "Extra", Country: "UA"})
+			return err
+		},
+		"rename": func() error {
+			name := "Renamed"
+			return targets.UpdateTarget(ctx, missionID, targetID, domain.TargetUpdate{Name: &name})
+		},
+		"notes": func() error { return targets.UpdateNotes(ctx, missionID, targetID, "leaked") },
+		"observation": func() error {
+			_, err := targets.AddObservation(ctx, missionID, targetID, "leaked")
+			return err
+		},
+		"complete": func() error { return targets.UpdateCompleteStatus(ctx, missionID, targetID, true) },
+		"delete":   func() error { return targets.DeleteTarget(ctx, missionID, targetID) },
+	}
+	for name, change := range changes {
+		if err := change(); !errors.Is(err, domain.ErrInsufficientClearance) {
+			t.Errorf("%s: got %v, want %v", name, err, domain.ErrInsufficientClearance)
+		}
+	}
+
+	mission, err = missions.GetMission(cleared(domain.ClassificationSecret), missionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mission.Targets) != 2 || mission.Targets[0].Notes != "notes of target 1" || mission.Targets[0].Complete {
+		t.Errorf("targets changed without clearance: %+v", mission.Targets)
+	}
+}
+
+func TestMoveTargetRejectsDeclassification(t *testing.T) {
+	store := newTestStore(t)
+	engine := newTestRules(t)
+	missions := NewMissionService(store, engine)
+	targets := NewTargetService(store, engine)
+	ctx := cleared(domain.ClassificationTopSecret)
+
+	secretID := createMission(t, missions, domain.ClassificationSecret, 2)
+	publicID := createMission(t, missions, domain.ClassificationPublic, 2)
+	secret, err := missions.GetMission(ctx, secretID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetID := secret.Targets[0].ID
+
+	_, err = targets.MoveTarget(ctx, secretID, targetID, publicID)
+	if !errors.Is(err, domain.ErrDeclassification) {
+		t.Fatalf("move to a lower classification: got %v, want %v", err, domain.ErrDeclassification)
+	}
+
+	target, err := targets.GetTarget(ctx, secretID, targetID)
+	if err != nil {
+		t.Fatalf("target left its mission: %v", err)
+	}
+	if target.Notes != secret.Targets[0].Notes {
+		t.Errorf("notes = %q, want %q", target.Notes, secret.Targets[0].Notes)
+	}
+
+	public, err := missions.GetMission(ctx, publicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved, err := targets.MoveTarget(ctx, publicID, public.Targets[0].ID, secretID)
+	if err != nil {
+		t.Fatalf("move to a higher classification: %v", err)
+	}
+	if moved.MissionID != secretID {
+		t.Errorf("moved target belongs to mission %d, want %d", moved.MissionID, secretID)
+	}
+}
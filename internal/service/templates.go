@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/auth"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// CreateTemplate stores a mission template. Like a mission, it is normal
+// priority and public unless set otherwise, may not be classified above the
+// caller's clearance and must have an allowed number of targets.
+func (s *MissionService) CreateTemplate(ctx context.Context, template domain.MissionTemplate) (int64, error) {
+	const op = "service.MissionService.CreateTemplate"
+
+	if err := validateStruct(template); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	levels := domain.Mission{Priority: template.Priority, Classification: template.Classification}
+	if err := setLevels(&levels); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	template.Priority, template.Classification = levels.Priority, levels.Classification
+	if err := checkClearance(ctx, template.Classification); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.rules.CheckTargetCount(len(template.Targets)); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	for i := range template.Targets {
+		code, err := countryCode(template.Targets[i].Country)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		template.Targets[i].Country = code
+	}
+
+	template.CreatedBy = auth.FromContext(ctx).Name
+	id, err := s.store.CreateMissionTemplate(ctx, template)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetTemplates lists every mission template by name, withholding the
+// target notes of those the caller is not cleared for.
+func (s *MissionService) GetTemplates(ctx context.Context) ([]domain.MissionTemplate, error) {
+	const op = "service.MissionService.GetTemplates"
+
+	templates, err := s.store.GetMissionTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range templates {
+		if checkClearance(ctx, templates[i].Classification) != nil {
+			templates[i].Redact()
+		}
+	}
+
+	return templates, nil
+}
+
+// GetTemplate returns a mission template, withholding the target notes
+// when the caller is not cleared for its classification.
+func (s *MissionService) GetTemplate(ctx context.Context, id int64) (*domain.MissionTemplate, error) {
+	const op = "service.MissionService.GetTemplate"
+
+	template, err := s.getTemplate(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if checkClearance(ctx, template.Classification) != nil {
+		template.Redact()
+	}
+
+	return template, nil
+}
+
+// DeleteTemplate removes a mission template. Missions created from it are
+// not affected. It requires clearance for the template's classification.
+func (s *MissionService) DeleteTemplate(ctx context.Context, id int64) error {
+	const op = "service.MissionService.DeleteTemplate"
+
+	template, err := s.getTemplate(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkClearance(ctx, template.Classification); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.store.DeleteMissionTemplate(ctx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CreateMissionFromTemplate creates an unassigned mission with the targets,
+// priority and classification of a template, subject to the same rules as
+// CreateMission.
+func (s *MissionService) CreateMissionFromTemplate(ctx context.Context, templateID int64) (int64, error) {
+	const op = "service.MissionService.CreateMissionFromTemplate"
+
+	template, err := s.getTemplate(ctx, templateID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkClearance(ctx, template.Classification); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	mission := domain.Mission{
+		Priority:       template.Priority,
+		Classification: template.Classification,
+		Targets:        make([]domain.Target, len(template.Targets)),
+	}
+	for i, t := range template.Targets {
+		mission.Targets[i] = domain.Target{Name: t.Name, Country: t.Country, Notes: t.Notes}
+	}
+
+	id, err := s.CreateMission(ctx, mission)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// CloneMission creates an unassigned, unscheduled mission with copies of
+// the targets of another mission, incomplete and without deadlines, subject
+// to the same rules as CreateMission. The notes are copied too, so the
+// caller must be cleared for the original mission.
+func (s *MissionService) CloneMission(ctx context.Context, id int64) (int64, error) {
+	const op = "service.MissionService.CloneMission"
+
+	original, err := getMission(ctx, s.store, id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkClearance(ctx, original.Classification); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	mission := domain.Mission{
+		Priority:       original.Priority,
+		Classification: original.Classification,
+		Targets:        make([]domain.Target, len(original.Targets)),
+	}
+	for i, t := range original.Targets {
+		mission.Targets[i] = domain.Target{
+			Name:      t.Name,
+			Country:   t.Country,
+			Notes:     t.Notes,
+			Address:   t.Address,
+			Latitude:  t.Latitude,
+			Longitude: t.Longitude,
+		}
+	}
+
+	cloneID, err := s.CreateMission(ctx, mission)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cloneID, nil
+}
+
+func (s *MissionService) getTemplate(ctx context.Context, id int64) (*domain.MissionTemplate, error) {
+	template, err := s.store.GetMissionTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, domain.ErrTemplateNotFound
+	}
+	return template, nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+func TestTemplatesRequireClearance(t *testing.T) {
+	store := newTestStore(t)
+	missions := NewMissionService(store, newTestRules(t))
+
+	secret := cleared(domain.ClassificationSecret)
+	templateID, err := missions.CreateTemplate(secret, domain.MissionTemplate{
+		Name:           "Embassy sweep",
+		Classification: domain.ClassificationSecret,
+		Targets:        []domain.TemplateTarget{{Name: "Attaché", Country: "UA", Notes: "meets at noon"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := cleared(domain.ClassificationConfidential)
+	template, err := missions.GetTemplate(ctx, templateID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !template.Redacted || template.Targets[0].Notes != "" {
+		t.Errorf("template not redacted for an uncleared caller: %+v", template)
+	}
+
+	if _, err := missions.CreateMissionFromTemplate(ctx, templateID); !errors.Is(err, domain.ErrInsufficientClearance) {
+		t.Errorf("create mission: got %v, want %v", err, domain.ErrInsufficientClearance)
+	}
+	if err := missions.DeleteTemplate(ctx, templateID); !errors.Is(err, domain.ErrInsufficientClearance) {
+		t.Errorf("delete: got %v, want %v", err, domain.ErrInsufficientClearance)
+	}
+
+	missionID, err := missions.CreateMissionFromTemplate(secret, templateID)
+	if err != nil {
+		t.Fatalf("create mission with clearance: %v", err)
+	}
+	mission, err := missions.GetMission(secret, missionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mission.Targets) != 1 || mission.Targets[0].Notes != "meets at noon" {
+		t.Errorf("mission targets = %+v, want the template's", mission.Targets)
+	}
+	if err := missions.DeleteTemplate(secret, templateID); err != nil {
+		t.Errorf("delete with clearance: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/rules"
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+// MaxImportRecords bounds how many cats and missions an import may have.
+const MaxImportRecords = 10000
+
+// errDryRun rolls back a dry run import.
+var errDryRun = errors.New("dry run")
+
+// TransferService exports and imports cats and missions in bulk.
+type TransferService struct {
+	store  storage.Store
+	breeds BreedCatalog
+	rules  *rules.Engine
+}
+
+func NewTransferService(store storage.Store, breeds BreedCatalog, rules *rules.Engine) *TransferService {
+	return &TransferService{store: store, breeds: breeds, rules: rules}
+}
+
+// ExportCats calls fn with every cat in ID order.
+func (s *TransferService) ExportCats(ctx context.Context, fn func(domain.SpyCat) error) error {
+	const op = "service.TransferService.ExportCats"
+
+	if err := s.store.EachCat(ctx, fn); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ExportMissions calls fn with every mission in ID order, withholding the
+// target notes and locations of those the caller is not cleared for.
+func (s *TransferService) ExportMissions(ctx context.Context, fn func(domain.Mission) error) error {
+	const op = "service.TransferService.ExportMissions"
+
+	err := s.store.EachMission(ctx, func(mission domain.Mission) error {
+		if checkClearance(ctx, mission.Classification) != nil {
+			mission.Redact()
+		}
+		return fn(mission)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Import creates the cats and missions of records, each as CreateCat and
+// CreateMission would, in a single transaction: every record is imported
+// or, when any fails, none is. A dry run checks every record the same way
+// and reports the failures without importing anything. Missions exported
+// without their notes and locations are rejected rather than imported
+// without them.
+func (s *TransferService) Import(ctx context.Context, records []domain.ImportRecord, dryRun bool) ([]domain.BatchResult, error) {
+	const op = "service.TransferService.Import"
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, &domain.ValidationError{Err: errors.New("the import has no cats or missions")})
+	}
+	if len(records) > MaxImportRecords {
+		return nil, fmt.Errorf("%s: %w", op, &domain.ValidationError{Err: fmt.Errorf("an import may have at most %d cats and missions", MaxImportRecords)})
+	}
+
+	// A dry run keeps going past failures, so that records that depend on a
+	// failed one report that rather than the original failure again.
+	mode := domain.BatchAtomic
+	if dryRun {
+		mode = domain.BatchPartial
+	}
+
+	// catRecords maps the IDs of the cats of the import to their records,
+	// and created maps them to the IDs they were created with.
+	catRecords := make(map[int64]int)
+	for i, r := range records {
+		if r.Cat != nil && r.Cat.ID != 0 {
+			catRecords[r.Cat.ID] = i
+		}
+	}
+	created := make(map[int64]int64)
+
+	var results []domain.BatchResult
+	err := s.store.WithTx(ctx, func(tx storage.Store) error {
+		var err error
+		results, err = runBatch(ctx, tx, mode, len(records), MaxImportRecords, func(tx storage.Store, i int) (int64, error) {
+			if cat := records[i].Cat; cat != nil {
+				id, err := (&CatService{store: tx, breeds: s.breeds}).CreateCat(ctx, cat.Name, cat.YearsOfExperience, cat.Breed, cat.Salary)
+				if err == nil && cat.ID != 0 {
+					created[cat.ID] = id
+				}
+				return id, err
+			}
+
+			mission := *records[i].Mission
+			if mission.Redacted {
+				return 0, &domain.ValidationError{Err: errors.New("the mission was exported without the notes and locations of its targets")}
+			}
+			if mission.CatID != nil {
+				if j, ok := catRecords[*mission.CatID]; ok {
+					id, ok := created[*mission.CatID]
+					if !ok && j > i {
+						return 0, &domain.ValidationError{Err: fmt.Errorf("cat %d of the import must come before the missions assigned to it", *mission.CatID)}
+					}
+					if !ok {
+						return 0, &domain.ValidationError{Err: fmt.Errorf("cat %d of the import was not imported", *mission.CatID)}
+					}
+					mission.CatID = &id
+				}
+			}
+			return (&MissionService{store: tx, rules: s.rules}).CreateMission(ctx, mission)
+		})
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// testBreeds is a breed catalog that knows only its own breeds, by ID.
+type testBreeds []domain.Breed
+
+func (b testBreeds) Resolve(breed string) (domain.Breed, bool) { return b.Breed(breed) }
+
+func (b testBreeds) Breed(id string) (domain.Breed, bool) {
+	for _, breed := range b {
+		if breed.ID == id {
+			return breed, true
+		}
+	}
+	return domain.Breed{}, false
+}
+
+func (b testBreeds) Search(string) []domain.Breed       { return nil }
+func (b testBreeds) Suggest(string, int) []domain.Breed { return nil }
+
+func newTestTransfer(t *testing.T) (*TransferService, *CatService, *MissionService) {
+	t.Helper()
+
+	store := newTestStore(t)
+	engine := newTestRules(t)
+	catalog := testBreeds{{ID: "beng", Name: "Bengal"}}
+	return NewTransferService(store, catalog, engine), NewCatService(store, catalog), NewMissionService(store, engine)
+}
+
+func importCat(id int64, breed string) domain.ImportRecord {
+	return domain.ImportRecord{Cat: &domain.SpyCat{ID: id, Name: "Shadow", YearsOfExperience: 5, Breed: breed, Salary: 1000}}
+}
+
+// importMission has a single target, or none when targets is false, and is
+// assigned to catID unless it is zero.
+func importMission(catID int64, targets bool) domain.ImportRecord {
+	mission := domain.Mission{}
+	if catID != 0 {
+		mission.CatID = &catID
+	}
+	if targets {
+		mission.Targets = []domain.Target{{Name: "Courier", Country: "UA", Notes: "takes the night train"}}
+	}
+	return domain.ImportRecord{Mission: &mission}
+}
+
+// countImported reports how many cats and missions the store has.
+func countImported(t *testing.T, cats *CatService, missions *MissionService) (int, int) {
+	t.Helper()
+
+	ctx := cleared(domain.ClassificationTopSecret)
+	allCats, err := cats.GetAllCats(ctx, domain.CatFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	allMissions, err := missions.GetAllMissions(ctx, domain.MissionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(allCats), len(allMissions)
+}
+
+func TestImport(t *testing.T) {
+	transfer, cats, missions := newTestTransfer(t)
+	ctx := cleared(domain.ClassificationTopSecret)
+
+	results, err := transfer.Import(ctx, []domain.ImportRecord{importCat(7, "beng"), importMission(7, true)}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domain.BatchFailed(results) {
+		t.Fatalf("import failed: %+v", results)
+	}
+
+	mission, err := missions.GetMission(ctx, results[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mission.CatID == nil || *mission.CatID != results[0].ID {
+		t.Errorf("mission is assigned to %v, want the imported cat %d", mission.CatID, results[0].ID)
+	}
+	if nc, nm := countImported(t, cats, missions); nc != 1 || nm != 1 {
+		t.Errorf("imported %d cats and %d missions, want 1 and 1", nc, nm)
+	}
+}
+
+func TestImportIsAtomic(t *testing.T) {
+	transfer, cats, missions := newTestTransfer(t)
+	ctx := cleared(domain.ClassificationTopSecret)
+
+	records := []domain.ImportRecord{importCat(7, "beng"), importMission(7, true), importMission(0, false)}
+	results, err := transfer.Import(ctx, records, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(records) {
+		t.Fatalf("got %d results, want %d", len(results), len(records))
+	}
+
+	if results[2].Err == nil || errors.Is(results[2].Err, domain.ErrBatchAborted) {
+		t.Errorf("mission without targets: got %v, want its own error", results[2].Err)
+	}
+	for _, i := range []int{0, 1} {
+		if !errors.Is(results[i].Err, domain.ErrBatchAborted) {
+			t.Errorf("record %d: got %v, want %v", i, results[i].Err, domain.ErrBatchAborted)
+		}
+	}
+	if nc, nm := countImported(t, cats, missions); nc != 0 || nm != 0 {
+		t.Errorf("failed import left %d cats and %d missions", nc, nm)
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	transfer, cats, missions := newTestTransfer(t)
+	ctx := cleared(domain.ClassificationTopSecret)
+
+	results, err := transfer.Import(ctx, []domain.ImportRecord{importCat(7, "beng"), importMission(7, true)}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domain.BatchFailed(results) {
+		t.Errorf("dry run failed: %+v", results)
+	}
+
+	// A dry run reports every failure, not only the first, and no record
+	// is reported as aborted because of another.
+	results, err = transfer.Import(ctx, []domain.ImportRecord{importCat(7, "tabby"), importMission(0, false), importMission(0, true)}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var breedErr *domain.BreedError
+	if !errors.As(results[0].Err, &breedErr) {
+		t.Errorf("unknown breed: got %v, want a breed error", results[0].Err)
+	}
+	if results[1].Err == nil {
+		t.Error("mission without targets passed the dry run")
+	}
+	if results[2].Err != nil {
+		t.Errorf("valid mission: %v", results[2].Err)
+	}
+
+	if nc, nm := countImported(t, cats, missions); nc != 0 || nm != 0 {
+		t.Errorf("dry runs imported %d cats and %d missions", nc, nm)
+	}
+}
+
+func TestImportCatReferences(t *testing.T) {
+	transfer, _, _ := newTestTransfer(t)
+	ctx := cleared(domain.ClassificationTopSecret)
+
+	records := []domain.ImportRecord{
+		importMission(7, true),
+		importCat(7, "beng"),
+		importCat(8, "tabby"),
+		importMission(8, true),
+	}
+	results, err := transfer.Import(ctx, records, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]string{
+		0: "must come before the missions assigned to it",
+		3: "was not imported",
+	}
+	for i, msg := range want {
+		var invalid *domain.ValidationError
+		if !errors.As(results[i].Err, &invalid) || !strings.Contains(results[i].Err.Error(), msg) {
+			t.Errorf("record %d: got %v, want a validation error saying %q", i, results[i].Err, msg)
+		}
+	}
+	if results[1].Err != nil {
+		t.Errorf("cat: %v", results[1].Err)
+	}
+}
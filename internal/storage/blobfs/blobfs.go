@@ -0,0 +1,192 @@
+// Package blobfs stores blobs as files in a local directory. Every blob is
+// named after the SHA-256 of its content and kept in a subdirectory named
+// after the first two hex digits, so no single directory grows too large.
+package blobfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+// tmpDir holds uploads until their hash is known. It lives under the root
+// so finished uploads can be renamed into place atomically.
+const tmpDir = "tmp"
+
+type Store struct {
+	root string
+}
+
+// New returns a Store keeping blobs under root, creating the directory if
+// needed.
+func New(root string) (*Store, error) {
+	const op = "storage.blobfs.New"
+
+	if err := os.MkdirAll(filepath.Join(root, tmpDir), 0o700); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Store{root: root}, nil
+}
+
+func (s *Store) Put(ctx context.Context, r io.Reader) (string, int64, error) {
+	const op = "storage.blobfs.Put"
+
+	tmp, err := os.CreateTemp(filepath.Join(s.root, tmpDir), "upload-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: create temp file: %w", op, err)
+	}
+	// Removing fails harmlessly once the file has been renamed into place.
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), contextReader{ctx: ctx, r: r})
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: write blob: %w", op, err)
+	}
+
+	key := hex.EncodeToString(hash.Sum(nil))
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", 0, fmt.Errorf("%s: %w", op, err)
+	}
+	// The same content may already be stored, in which case the rename
+	// replaces it with an identical file.
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, fmt.Errorf("%s: store blob: %w", op, err)
+	}
+
+	return key, size, nil
+}
+
+func (s *Store) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	const op = "storage.blobfs.Open"
+
+	if !validKey(key) {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrBlobNotFound)
+	}
+
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrBlobNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return f, nil
+}
+
+func (s *Store) Exists(_ context.Context, key string) (bool, error) {
+	const op = "storage.blobfs.Exists"
+
+	if !validKey(key) {
+		return false, nil
+	}
+
+	_, err := os.Stat(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return true, nil
+}
+
+func (s *Store) Delete(_ context.Context, key string) error {
+	const op = "storage.blobfs.Delete"
+
+	if !validKey(key) {
+		return nil
+	}
+
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// List returns every stored blob. Unfinished uploads are not included.
+func (s *Store) List(ctx context.Context) ([]storage.BlobInfo, error) {
+	const op = "storage.blobfs.List"
+
+	var blobs []storage.BlobInfo
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != s.root && d.Name() == tmpDir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !validKey(d.Name()) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, storage.BlobInfo{Key: d.Name(), Size: info.Size(), StoredAt: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return blobs, nil
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.root, key[:2], key)
+}
+
+// validKey reports whether key is a hex encoded SHA-256. Keys are used in
+// file paths, so anything else is refused.
+func validKey(key string) bool {
+	if len(key) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range key {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// contextReader stops reading once ctx is done, so an upload from a client
+// that went away is not written to disk to the end.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
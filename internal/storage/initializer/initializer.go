@@ -1,17 +1,103 @@
 package initializer
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 
+	"github.com/illiakornyk/spy-cat/internal/config"
+	"github.com/illiakornyk/spy-cat/internal/envelope"
+	"github.com/illiakornyk/spy-cat/internal/storage/blobfs"
 	"github.com/illiakornyk/spy-cat/internal/storage/sqlite"
 )
 
-func InitializeStorage(storagePath string, logger *slog.Logger) *sqlite.Storage {
-	storage, err := sqlite.New(storagePath)
+func InitializeStorage(cfg *config.Config, logger *slog.Logger) *sqlite.Storage {
+	keyring, err := notesKeyring(cfg.Encryption, cfg.Env)
+	if err != nil {
+		logger.Error("Invalid encryption configuration", slog.Any("error", err))
+		os.Exit(1)
+	}
+	switch {
+	case keyring != nil:
+	case cfg.Encryption.ActiveKey != "":
+		logger.Warn("No key provided for the active encryption key, target notes are stored in plain text; set NOTES_KEK to encrypt them",
+			slog.String("active_key", cfg.Encryption.ActiveKey),
+			slog.String("env", cfg.Env),
+		)
+	default:
+		logger.Warn("No encryption key configured, target notes are stored in plain text")
+	}
+
+	storage, err := sqlite.New(cfg.StoragePath, sqlite.Options{
+		MigrationsPath: cfg.MigrationsPath,
+		Timeouts: sqlite.Timeouts{
+			Default:    cfg.QueryTimeouts.Default,
+			Operations: cfg.QueryTimeouts.Operations,
+		},
+		Notes: keyring,
+	})
 	if err != nil {
 		logger.Error("Failed to open SQLite database", slog.Any("error", err))
 		os.Exit(1)
 	}
+
+	version, dirty, err := storage.MigrationVersion(context.Background())
+	if err != nil {
+		logger.Error("Failed to read schema version", slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger.Info("Database schema ready", slog.Uint64("version", uint64(version)), slog.Bool("dirty", dirty))
+
 	return storage
 }
+
+// InitializeBlobStore opens the directory attachment content is kept in,
+// which defaults to an "attachments" directory next to the database.
+func InitializeBlobStore(cfg *config.Config, logger *slog.Logger) *blobfs.Store {
+	path := cfg.Attachments.Path
+	if path == "" {
+		path = filepath.Join(filepath.Dir(cfg.StoragePath), "attachments")
+	}
+
+	blobs, err := blobfs.New(path)
+	if err != nil {
+		logger.Error("Failed to open attachment storage", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	return blobs
+}
+
+// notesKeyring decodes the configured key-encryption keys. It returns nil
+// when encryption is not enabled, or in the local env when the active key
+// has not been provided; anywhere else a missing key is an error, so a
+// deployment never silently falls back to plain text.
+func notesKeyring(cfg config.Encryption, env string) (*envelope.Keyring, error) {
+	if len(cfg.Keys) == 0 && cfg.ActiveKey == "" {
+		return nil, nil
+	}
+	if cfg.ActiveKey == "" {
+		return nil, errors.New("encryption keys are configured but active_key is not set")
+	}
+	if _, ok := cfg.Keys[cfg.ActiveKey]; !ok {
+		if env == config.EnvLocal && len(cfg.Keys) == 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("no key is provided for active_key %q, set NOTES_KEK", cfg.ActiveKey)
+	}
+
+	keys := make(map[string][]byte, len(cfg.Keys))
+	for id, encoded := range cfg.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not valid base64: %w", id, err)
+		}
+		keys[id] = key
+	}
+
+	return envelope.NewKeyring(cfg.ActiveKey, keys)
+}
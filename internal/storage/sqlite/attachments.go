@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+const attachmentColumns = "id, target_id, filename, media_type, size, sha256, uploaded_by, created_at"
+
+// AddAttachment records an attachment whose content is already in blob
+// storage. The ID and creation time of attachment are ignored.
+func (s *Storage) AddAttachment(ctx context.Context, attachment domain.Attachment) (int64, error) {
+	const op = "storage.sqlite.AddAttachment"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx,
+		"INSERT INTO target_attachments (target_id, filename, media_type, size, sha256, uploaded_by, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		attachment.TargetID, attachment.Filename, attachment.MediaType, attachment.Size, attachment.SHA256, attachment.UploadedBy, now())
+	if err != nil {
+		return 0, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetAttachment returns the attachment with the given ID, or nil when there
+// is none.
+func (s *Storage) GetAttachment(ctx context.Context, id int64) (*domain.Attachment, error) {
+	const op = "storage.sqlite.GetAttachment"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	attachment, err := scanAttachment(s.db.QueryRowContext(ctx, "SELECT "+attachmentColumns+" FROM target_attachments WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: query row: %w", op, err)
+	}
+
+	return &attachment, nil
+}
+
+// GetAttachments returns the attachments of a target in upload order.
+func (s *Storage) GetAttachments(ctx context.Context, targetID int64) ([]domain.Attachment, error) {
+	const op = "storage.sqlite.GetAttachments"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+attachmentColumns+" FROM target_attachments WHERE target_id = ? ORDER BY id", targetID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var attachments []domain.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate: %w", op, err)
+	}
+
+	return attachments, nil
+}
+
+func (s *Storage) DeleteAttachment(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DeleteAttachment"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM target_attachments WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrAttachmentNotFound)
+	}
+
+	return nil
+}
+
+func (s *Storage) AttachmentBlobInUse(ctx context.Context, sha256 string) (bool, error) {
+	const op = "storage.sqlite.AttachmentBlobInUse"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	var inUse bool
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM target_attachments WHERE sha256 = ?)", sha256).Scan(&inUse)
+	if err != nil {
+		return false, fmt.Errorf("%s: query row: %w", op, err)
+	}
+
+	return inUse, nil
+}
+
+func scanAttachment(row rowScanner) (domain.Attachment, error) {
+	var a domain.Attachment
+	err := row.Scan(&a.ID, &a.TargetID, &a.Filename, &a.MediaType, &a.Size, &a.SHA256, &a.UploadedBy, &a.CreatedAt)
+	return a, err
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+const auditColumns = "id, action, actor, mission_id, target_id, details, created_at"
+
+// AddAuditEntry appends entry to the audit log and returns its ID. The ID
+// and creation time of entry are ignored.
+func (s *Storage) AddAuditEntry(ctx context.Context, entry domain.AuditEntry) (int64, error) {
+	const op = "storage.sqlite.AddAuditEntry"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return 0, fmt.Errorf("%s: encode details: %w", op, err)
+	}
+	if entry.Details == nil {
+		details = []byte("{}")
+	}
+
+	res, err := s.db.ExecContext(ctx,
+		"INSERT INTO audit_log (action, actor, mission_id, target_id, details, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		entry.Action, entry.Actor, entry.MissionID, entry.TargetID, string(details), now())
+	if err != nil {
+		return 0, fmt.Errorf("%s: insert: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetAuditEntries returns a page of the audit log entries matching filter,
+// newest first, together with the total number of matching entries.
+func (s *Storage) GetAuditEntries(ctx context.Context, filter domain.AuditFilter, limit, offset int) ([]domain.AuditEntry, int, error) {
+	const op = "storage.sqlite.GetAuditEntries"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	var w where
+	if filter.Action != "" {
+		w.add("action = ?", filter.Action)
+	}
+	if filter.MissionID != nil {
+		w.add("mission_id = ?", *filter.MissionID)
+	}
+	if filter.TargetID != nil {
+		w.add("target_id = ?", *filter.TargetID)
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_log"+w.String(), w.args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%s: count entries: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+auditColumns+" FROM audit_log"+w.String()+" ORDER BY id DESC LIMIT ? OFFSET ?",
+		append(w.args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: query entries: %w", op, err)
+	}
+	defer rows.Close()
+
+	var entries []domain.AuditEntry
+	for rows.Next() {
+		var e domain.AuditEntry
+		var details string
+		if err := rows.Scan(&e.ID, &e.Action, &e.Actor, &e.MissionID, &e.TargetID, &details, &e.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("%s: scan entry: %w", op, err)
+		}
+		if err := json.Unmarshal([]byte(details), &e.Details); err != nil {
+			return nil, 0, fmt.Errorf("%s: entry %d: decode details: %w", op, e.ID, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: iterate entries: %w", op, err)
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// TargetCountries counts the targets recorded in each distinct country
+// value.
+func (s *Storage) TargetCountries(ctx context.Context) (map[string]int, error) {
+	const op = "storage.sqlite.TargetCountries"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT country, COUNT(*) FROM targets GROUP BY country")
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var country string
+		var count int
+		if err := rows.Scan(&country, &count); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		counts[country] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate: %w", op, err)
+	}
+
+	return counts, nil
+}
+
+// RenameTargetCountry replaces the country value from with to on every
+// target and returns the number of targets changed.
+func (s *Storage) RenameTargetCountry(ctx context.Context, from, to string) (int64, error) {
+	const op = "storage.sqlite.RenameTargetCountry"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "UPDATE targets SET country = ? WHERE country = ?", to, from)
+	if err != nil {
+		return 0, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	return rowsAffected, nil
+}
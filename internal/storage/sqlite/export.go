@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// EachCat calls fn with every cat in ID order. Cats are read one at a time,
+// so the table is never held in memory; an error from fn stops the scan and
+// is returned. An export takes as long as the data and the client reading
+// it do, so unlike other operations it has no configured timeout and is
+// only bounded by ctx.
+func (s *Storage) EachCat(ctx context.Context, fn func(domain.SpyCat) error) error {
+	const op = "storage.sqlite.EachCat"
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+catColumns+" FROM spy_cats ORDER BY id")
+	if err != nil {
+		return fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		cat, err := scanCat(rows)
+		if err != nil {
+			return fmt.Errorf("%s: scan: %w", op, err)
+		}
+		if err := fn(cat); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: iterate: %w", op, err)
+	}
+
+	return nil
+}
+
+// EachMission calls fn with every mission and its targets in ID order,
+// reading one mission at a time and only bounded by ctx, like EachCat.
+func (s *Storage) EachMission(ctx context.Context, fn func(domain.Mission) error) error {
+	const op = "storage.sqlite.EachMission"
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+missionColumns+" FROM missions ORDER BY id")
+	if err != nil {
+		return fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		mission, err := scanMission(rows)
+		if err != nil {
+			return fmt.Errorf("%s: scan mission: %w", op, err)
+		}
+		if mission.Targets, err = s.getTargetsForMission(ctx, mission.ID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if err := fn(mission); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: iterate missions: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build !sqlite_fts5
+
+package sqlite
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestMain fails the package straight away when SQLite is built without
+// FTS5, which every migrated database needs, instead of letting each test
+// fail on its own.
+func TestMain(m *testing.M) {
+	fmt.Fprintln(os.Stderr, "these tests need SQLite with FTS5: run them with -tags sqlite_fts5, e.g. make test")
+	os.Exit(1)
+}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// IntegrityViolation describes a single problem found by CheckIntegrity.
+type IntegrityViolation struct {
+	Table  string `json:"table"`
+	RowID  int64  `json:"row_id,omitempty"`
+	Parent string `json:"parent,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// quarantineTables lists the tables the schema-constraints migration moved
+// the rows its constraints would reject to, by the table they came from.
+var quarantineTables = []struct{ table, quarantine string }{
+	{"spy_cats", "quarantined_spy_cats"},
+	{"targets", "quarantined_targets"},
+}
+
+// CheckIntegrity runs SQLite's structural integrity check and reports every
+// row whose foreign key points at a missing parent, and every row that was
+// quarantined because it violated the constraints added later. Such rows
+// may predate foreign key enforcement and are not removed automatically.
+func (s *Storage) CheckIntegrity(ctx context.Context) ([]IntegrityViolation, error) {
+	const op = "storage.sqlite.CheckIntegrity"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	var violations []IntegrityViolation
+
+	rows, err := s.db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("%s: integrity check: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return nil, fmt.Errorf("%s: scan integrity check: %w", op, err)
+		}
+		if result != "ok" {
+			violations = append(violations, IntegrityViolation{Detail: result})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	fkRows, err := s.db.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("%s: foreign key check: %w", op, err)
+	}
+	defer fkRows.Close()
+
+	for fkRows.Next() {
+		var table, parent string
+		var rowID, fkID int64
+		if err := fkRows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return nil, fmt.Errorf("%s: scan foreign key check: %w", op, err)
+		}
+		violations = append(violations, IntegrityViolation{
+			Table:  table,
+			RowID:  rowID,
+			Parent: parent,
+			Detail: fmt.Sprintf("row %d in %s references a missing row in %s", rowID, table, parent),
+		})
+	}
+
+	if err := fkRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	for _, q := range quarantineTables {
+		quarantined, err := s.quarantinedRows(ctx, q.table, q.quarantine)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		violations = append(violations, quarantined...)
+	}
+
+	return violations, nil
+}
+
+func (s *Storage) quarantinedRows(ctx context.Context, table, quarantine string) ([]IntegrityViolation, error) {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf("SELECT id, reason FROM %s ORDER BY id", quarantine))
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", quarantine, err)
+	}
+	defer rows.Close()
+
+	var violations []IntegrityViolation
+	for rows.Next() {
+		var rowID int64
+		var reason string
+		if err := rows.Scan(&rowID, &reason); err != nil {
+			return nil, fmt.Errorf("scan %s: %w", quarantine, err)
+		}
+		violations = append(violations, IntegrityViolation{
+			Table:  table,
+			RowID:  rowID,
+			Detail: fmt.Sprintf("row %d of %s was moved to %s: %s", rowID, table, quarantine, reason),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return violations, nil
+}
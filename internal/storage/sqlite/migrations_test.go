@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/illiakornyk/spy-cat/migrations"
+)
+
+// legacyVersion is the last migration before the schema-constraints one.
+const legacyVersion = 20240705101430
+
+// TestMigrateLegacyRows migrates a database holding rows the constraints
+// added later reject, and checks they are quarantined rather than failing
+// the migration.
+func TestMigrateLegacyRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := migrate.NewWithInstance("iofs", source, "sqlite3", driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Migrate(legacyVersion); err != nil {
+		t.Fatal(err)
+	}
+
+	fixture, err := os.ReadFile(filepath.Join("testdata", "legacy.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(string(fixture)); err != nil {
+		t.Fatal(err)
+	}
+	m.Close()
+
+	s, err := New(path, Options{})
+	if err != nil {
+		t.Fatalf("migrate legacy database: %v", err)
+	}
+	t.Cleanup(func() { s.pool.Close() })
+
+	ctx := context.Background()
+	if _, dirty, err := s.MigrationVersion(ctx); err != nil || dirty {
+		t.Fatalf("MigrationVersion: dirty %v, err %v", dirty, err)
+	}
+
+	if got := ids(t, s, "SELECT id FROM spy_cats ORDER BY id"); !slices.Equal(got, []int64{1, 2}) {
+		t.Errorf("spy_cats = %v, want [1 2]", got)
+	}
+	if got := ids(t, s, "SELECT id FROM quarantined_spy_cats ORDER BY id"); !slices.Equal(got, []int64{3, 4, 5}) {
+		t.Errorf("quarantined_spy_cats = %v, want [3 4 5]", got)
+	}
+	if got := ids(t, s, "SELECT id FROM targets ORDER BY id"); !slices.Equal(got, []int64{1, 2}) {
+		t.Errorf("targets = %v, want [1 2]", got)
+	}
+	if got := ids(t, s, "SELECT id FROM quarantined_targets ORDER BY id"); !slices.Equal(got, []int64{3, 4, 5}) {
+		t.Errorf("quarantined_targets = %v, want [3 4 5]", got)
+	}
+
+	violations, err := s.CheckIntegrity(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var quarantined []string
+	for _, v := range violations {
+		if v.Parent == "" {
+			quarantined = append(quarantined, v.Detail)
+		}
+	}
+	want := []string{
+		"row 3 of spy_cats was moved to quarantined_spy_cats: salary is missing",
+		"row 4 of spy_cats was moved to quarantined_spy_cats: salary is not positive",
+		"row 5 of spy_cats was moved to quarantined_spy_cats: name is empty",
+		"row 3 of targets was moved to quarantined_targets: mission_id is missing",
+		"row 4 of targets was moved to quarantined_targets: name is missing",
+		"row 5 of targets was moved to quarantined_targets: country is missing",
+	}
+	if !slices.Equal(quarantined, want) {
+		t.Errorf("CheckIntegrity reported\n%q\nwant\n%q", quarantined, want)
+	}
+
+	// New rows continue after every id ever used, so the quarantined rows
+	// and rows deleted before the migration keep their ids free.
+	inserts := []struct{ table, query string }{
+		{"spy_cats", "INSERT INTO spy_cats (name, years_of_experience, breed, salary) VALUES ('Garfield', 1, 'Persian', 100)"},
+		{"missions", "INSERT INTO missions (complete) VALUES (0)"},
+		{"targets", "INSERT INTO targets (mission_id, name, country) VALUES (1, 'Odie', 'UA')"},
+	}
+	wantIDs := map[string]int64{"spy_cats": 7, "missions": 4, "targets": 7}
+	for _, insert := range inserts {
+		res, err := s.pool.Exec(insert.query)
+		if err != nil {
+			t.Fatalf("insert into %s: %v", insert.table, err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != wantIDs[insert.table] {
+			t.Errorf("new row in %s got id %d, want %d", insert.table, id, wantIDs[insert.table])
+		}
+	}
+}
+
+func ids(t *testing.T, s *Storage, query string) []int64 {
+	t.Helper()
+
+	rows, err := s.pool.Query(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
@@ -1,11 +1,14 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/illiakornyk/spy-cat/internal/common"
+	"github.com/illiakornyk/spy-cat/internal/domain"
 )
 
 
@@ -13,73 +16,125 @@ import (
 
 
 
-func (s *Storage) CreateMission(catID sql.NullInt64, targets []common.Target, complete bool) (int64, error) {
+// CreateMission inserts the mission and its targets. ID and the timestamps
+// of mission are ignored. Priority and classification must be set.
+func (s *Storage) CreateMission(ctx context.Context, mission domain.Mission) (int64, error) {
     const op = "storage.sqlite.CreateMission"
 
-    if catID.Valid {
-        // Check if the cat is already assigned to an active mission
-        isAssigned, err := s.isCatAssignedToActiveMission(catID.Int64)
+    ctx, cancel := s.opContext(ctx, op)
+    defer cancel()
+
+    var missionID int64
+    err := s.inTx(ctx, func(tx *Storage) error {
+        stmt, err := tx.db.PrepareContext(ctx, "INSERT INTO missions (cat_id, complete, assigned_at, completed_at, created_at, updated_at, starts_at, due_at, priority, classification) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
         if err != nil {
-            return 0, fmt.Errorf("%s: check if cat is assigned to active mission: %w", op, err)
+            return fmt.Errorf("prepare statement: %w", err)
         }
-        if isAssigned {
-            return 0, fmt.Errorf("%s: cat is already assigned to an active mission", op)
+        defer stmt.Close()
+
+        createdAt := now()
+        var assignedAt sql.NullTime
+        if mission.CatID != nil {
+            assignedAt = sql.NullTime{Time: createdAt, Valid: true}
         }
-    }
 
-    if len(targets) < 1 || len(targets) > 3 {
-        return 0, fmt.Errorf("%s: the number of targets must be between 1 and 3", op)
-    }
+        res, err := stmt.ExecContext(ctx, mission.CatID, mission.Complete, assignedAt, completedAt(mission.Complete), createdAt, createdAt,
+            nullTime(mission.StartsAt), nullTime(mission.DueAt), mission.Priority, mission.Classification)
+        if err != nil {
+            return fmt.Errorf("execute statement: %w", err)
+        }
 
-    stmt, err := s.db.Prepare("INSERT INTO missions (cat_id, complete) VALUES (?, ?)")
-    if err != nil {
-        return 0, fmt.Errorf("%s: prepare statement: %w", op, err)
-    }
-    defer stmt.Close()
+        missionID, err = res.LastInsertId()
+        if err != nil {
+            return fmt.Errorf("failed to get last insert id: %w", err)
+        }
 
-    res, err := stmt.Exec(catID, complete)
-    if err != nil {
-        return 0, fmt.Errorf("%s: execute statement: %w", op, err)
-    }
+        if mission.CatID != nil {
+            if err := tx.recordAssignment(ctx, missionID, *mission.CatID, assignedAt.Time); err != nil {
+                return err
+            }
+        }
+
+        for _, target := range mission.Targets {
+            _, err := tx.AddTarget(ctx, missionID, target)
+            if err != nil {
+                return fmt.Errorf("failed to add target: %w", err)
+            }
+        }
 
-    missionID, err := res.LastInsertId()
+        return nil
+    })
     if err != nil {
-        return 0, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+        return 0, fmt.Errorf("%s: %w", op, err)
     }
 
-    for _, target := range targets {
-        _, err := s.AddTarget(missionID, target.Name, target.Country, target.Notes)
+    return missionID, nil
+}
+
+
+
+// overdueCondition matches incomplete missions due before its argument.
+const overdueCondition = "complete = 0 AND due_at IS NOT NULL AND due_at < ?"
+
+// FlagOverdueMissions marks the missions that became overdue since the last
+// call, recording asOf as the time they were found, and returns them. A
+// mission is only ever flagged once.
+func (s *Storage) FlagOverdueMissions(ctx context.Context, asOf time.Time) ([]domain.OverdueMission, error) {
+    const op = "storage.sqlite.FlagOverdueMissions"
+
+    ctx, cancel := s.opContext(ctx, op)
+    defer cancel()
+
+    asOf = asOf.UTC()
+    var overdue []domain.OverdueMission
+    err := s.inTx(ctx, func(tx *Storage) error {
+        rows, err := tx.db.QueryContext(ctx, "SELECT id, cat_id, due_at FROM missions WHERE "+overdueCondition+" AND overdue_at IS NULL ORDER BY due_at", asOf)
         if err != nil {
-            return 0, fmt.Errorf("%s: failed to add target: %w", op, err)
+            return fmt.Errorf("query missions: %w", err)
         }
-    }
+        defer rows.Close()
 
-    return missionID, nil
-}
+        for rows.Next() {
+            var m domain.OverdueMission
+            if err := rows.Scan(&m.MissionID, &m.CatID, &m.DueAt); err != nil {
+                return fmt.Errorf("scan mission: %w", err)
+            }
+            overdue = append(overdue, m)
+        }
+        if err := rows.Err(); err != nil {
+            return fmt.Errorf("iterate missions: %w", err)
+        }
+        rows.Close()
 
+        for _, m := range overdue {
+            if _, err := tx.db.ExecContext(ctx, "UPDATE missions SET overdue_at = ? WHERE id = ?", asOf, m.MissionID); err != nil {
+                return fmt.Errorf("flag mission %d: %w", m.MissionID, err)
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", op, err)
+    }
 
+    return overdue, nil
+}
 
-func (s *Storage) UpdateMissionCompleteStatus(id int64, complete bool) error {
+func (s *Storage) UpdateMissionCompleteStatus(ctx context.Context, id int64, complete bool) error {
 	const op = "storage.sqlite.UpdateMissionCompleteStatus"
 
-	if complete {
-		// Check if all targets are completed before marking the mission as complete
-		allComplete, err := s.areAllTargetsComplete(id)
-		if err != nil {
-			return fmt.Errorf("%s: check if all targets are complete: %w", op, err)
-		}
-		if !allComplete {
-			return fmt.Errorf("%s: cannot complete mission until all targets are completed", op)
-		}
-	}
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
 
-	stmt, err := s.db.Prepare("UPDATE missions SET complete = ? WHERE id = ?")
+	// Marking a complete mission complete again keeps its original time.
+	stmt, err := s.db.PrepareContext(ctx, "UPDATE missions SET complete = ?, completed_at = CASE WHEN ? THEN COALESCE(completed_at, ?) END, updated_at = ? WHERE id = ?")
 	if err != nil {
 		return fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(complete, id)
+	updatedAt := now()
+	_, err = stmt.ExecContext(ctx, complete, complete, updatedAt, updatedAt, id)
 	if err != nil {
 		return fmt.Errorf("%s: execute statement: %w", op, err)
 	}
@@ -87,58 +142,88 @@ func (s *Storage) UpdateMissionCompleteStatus(id int64, complete bool) error {
 	return nil
 }
 
-func (s *Storage) AssignCatToMission(missionID, catID int64) error {
-    const op = "storage.sqlite.AssignCatToMission"
+// UpdateMissionLevels sets the priority and classification of a mission.
+func (s *Storage) UpdateMissionLevels(ctx context.Context, id int64, priority domain.Priority, classification domain.Classification) error {
+	const op = "storage.sqlite.UpdateMissionLevels"
 
-    // Check if the mission exists
-    var exists bool
-    err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM missions WHERE id = ?)", missionID).Scan(&exists)
-    if err != nil {
-        return fmt.Errorf("%s: query mission: %w", op, err)
-    }
-    if !exists {
-        return fmt.Errorf("%s: mission does not exist", op)
-    }
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
 
-    // Check if the cat exists
-    err = s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM spy_cats WHERE id = ?)", catID).Scan(&exists)
-    if err != nil {
-        return fmt.Errorf("%s: query cat: %w", op, err)
-    }
-    if !exists {
-        return fmt.Errorf("%s: cat does not exist", op)
-    }
+	res, err := s.db.ExecContext(ctx, "UPDATE missions SET priority = ?, classification = ?, updated_at = ? WHERE id = ?", priority, classification, now(), id)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
 
-    // Check if the cat is already assigned to an active mission
-    isAssigned, err := s.isCatAssignedToActiveMission(catID)
-    if err != nil {
-        return fmt.Errorf("%s: check if cat is assigned to active mission: %w", op, err)
-    }
-    if isAssigned {
-        return fmt.Errorf("%s: cat is already assigned to an active mission", op)
-    }
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrMissionNotFound)
+	}
 
-    // Assign the cat to the mission
-    stmt, err := s.db.Prepare("UPDATE missions SET cat_id = ? WHERE id = ?")
-    if err != nil {
-        return fmt.Errorf("%s: prepare statement: %w", op, err)
-    }
-    defer stmt.Close()
+	return nil
+}
+
+func (s *Storage) AssignCatToMission(ctx context.Context, missionID, catID int64) error {
+    const op = "storage.sqlite.AssignCatToMission"
+
+    ctx, cancel := s.opContext(ctx, op)
+    defer cancel()
+
+    err := s.inTx(ctx, func(tx *Storage) error {
+        assignedAt := now()
+
+        _, err := tx.db.ExecContext(ctx, "UPDATE missions SET cat_id = ?, assigned_at = ?, updated_at = ? WHERE id = ?", catID, assignedAt, assignedAt, missionID)
+        if err != nil {
+            return fmt.Errorf("update mission: %w", err)
+        }
 
-    _, err = stmt.Exec(catID, missionID)
+        return tx.recordAssignment(ctx, missionID, catID, assignedAt)
+    })
     if err != nil {
-        return fmt.Errorf("%s: execute statement: %w", op, err)
+        return fmt.Errorf("%s: %w", op, err)
     }
 
     return nil
 }
 
+// recordAssignment ends the current assignment of a mission, if any, and
+// starts one for catID in mission_assignments, so the previous cat keeps
+// the mission in its history. Assigning the cat the mission already has
+// leaves the history unchanged.
+func (s *Storage) recordAssignment(ctx context.Context, missionID, catID int64, at time.Time) error {
+	var currentCatID int64
+	err := s.db.QueryRowContext(ctx, "SELECT cat_id FROM mission_assignments WHERE mission_id = ? AND unassigned_at IS NULL", missionID).Scan(&currentCatID)
+	if err == nil && currentCatID == catID {
+		return nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("query current assignment: %w", err)
+	}
 
-func (s *Storage) MissionExists(id int64) (bool, error) {
+	_, err = s.db.ExecContext(ctx, "UPDATE mission_assignments SET unassigned_at = ? WHERE mission_id = ? AND unassigned_at IS NULL", at, missionID)
+	if err != nil {
+		return fmt.Errorf("end current assignment: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, "INSERT INTO mission_assignments (mission_id, cat_id, assigned_at) VALUES (?, ?, ?)", missionID, catID, at)
+	if err != nil {
+		return fmt.Errorf("record assignment: %w", err)
+	}
+
+	return nil
+}
+
+
+func (s *Storage) MissionExists(ctx context.Context, id int64) (bool, error) {
 	const op = "storage.sqlite.MissionExists"
 
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
 	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM missions WHERE id = ?)", id).Scan(&exists)
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM missions WHERE id = ?)", id).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("%s: query mission: %w", op, err)
 	}
@@ -149,57 +234,42 @@ func (s *Storage) MissionExists(id int64) (bool, error) {
 
 
 // DeleteMission always deletes the mission, regardless of whether it is assigned to a cat.
-func (s *Storage) DeleteMission(missionIDs []int64) error {
+func (s *Storage) DeleteMission(ctx context.Context, missionIDs []int64) error {
     const op = "storage.sqlite.DeleteMission"
 
-    // Begin transaction
-    tx, err := s.db.Begin()
-    if err != nil {
-        return fmt.Errorf("%s: begin transaction: %w", op, err)
-    }
+    ctx, cancel := s.opContext(ctx, op)
+    defer cancel()
 
-    // Use the internal function to perform the deletion within the transaction
-    err = s.deleteMissionTx(tx, missionIDs, true)
+    err := s.inTx(ctx, func(tx *Storage) error {
+        return tx.deleteMissionTx(ctx, missionIDs, true)
+    })
     if err != nil {
-        tx.Rollback()
         return fmt.Errorf("%s: %w", op, err)
     }
 
-    // Commit transaction
-    if err := tx.Commit(); err != nil {
-        return fmt.Errorf("%s: commit transaction: %w", op, err)
-    }
-
     return nil
 }
 
 // DeleteUnassignedMission only deletes the mission if it is not assigned to a cat.
-func (s *Storage) DeleteUnassignedMission(missionIDs []int64) error {
+func (s *Storage) DeleteUnassignedMission(ctx context.Context, missionIDs []int64) error {
     const op = "storage.sqlite.DeleteUnassignedMission"
 
-    // Begin transaction
-    tx, err := s.db.Begin()
-    if err != nil {
-        return fmt.Errorf("%s: begin transaction: %w", op, err)
-    }
+    ctx, cancel := s.opContext(ctx, op)
+    defer cancel()
 
-    // Use the internal function to perform the deletion within the transaction
-    err = s.deleteMissionTx(tx, missionIDs, false)
+    err := s.inTx(ctx, func(tx *Storage) error {
+        return tx.deleteMissionTx(ctx, missionIDs, false)
+    })
     if err != nil {
-        tx.Rollback()
         return fmt.Errorf("%s: %w", op, err)
     }
 
-    // Commit transaction
-    if err := tx.Commit(); err != nil {
-        return fmt.Errorf("%s: commit transaction: %w", op, err)
-    }
-
     return nil
 }
 
-// Internal function for deleting a mission within a transaction context
-func (s *Storage) deleteMissionTx(tx *sql.Tx, missionIDs []int64, ignoreAssigned bool) error {
+// Internal function for deleting a mission, must be called on a Storage
+// bound to a transaction
+func (s *Storage) deleteMissionTx(ctx context.Context, missionIDs []int64, ignoreAssigned bool) error {
     const op = "storage.sqlite.DeleteMissionTx"
 
     if len(missionIDs) == 0 {
@@ -215,7 +285,7 @@ func (s *Storage) deleteMissionTx(tx *sql.Tx, missionIDs []int64, ignoreAssigned
 
     // Query to select cat_id for each mission
     query := fmt.Sprintf("SELECT id, cat_id FROM missions WHERE id IN (%s)", placeholderString)
-    rows, err := tx.Query(query, int64SliceToInterfaceSlice(missionIDs)...)
+    rows, err := s.db.QueryContext(ctx, query, int64SliceToInterfaceSlice(missionIDs)...)
     if err != nil {
         return fmt.Errorf("%s: query mission: %w", op, err)
     }
@@ -230,7 +300,7 @@ func (s *Storage) deleteMissionTx(tx *sql.Tx, missionIDs []int64, ignoreAssigned
             return fmt.Errorf("%s: scan mission: %w", op, err)
         }
         if !ignoreAssigned && catID.Valid {
-            return fmt.Errorf("%s: cannot delete a mission assigned to a cat", op)
+            return fmt.Errorf("%s: %w", op, domain.ErrMissionAssigned)
         }
         validMissionIDs = append(validMissionIDs, id)
     }
@@ -242,17 +312,20 @@ func (s *Storage) deleteMissionTx(tx *sql.Tx, missionIDs []int64, ignoreAssigned
     if len(validMissionIDs) == 0 {
         return nil
     }
+    // Missions that do not exist are skipped, so the deletes take fewer
+    // arguments than the query above.
+    placeholderString = strings.Join(placeholders[:len(validMissionIDs)], ", ")
 
     // Delete targets associated with the missions
     deleteTargetsQuery := fmt.Sprintf("DELETE FROM targets WHERE mission_id IN (%s)", placeholderString)
-    _, err = tx.Exec(deleteTargetsQuery, int64SliceToInterfaceSlice(validMissionIDs)...)
+    _, err = s.db.ExecContext(ctx, deleteTargetsQuery, int64SliceToInterfaceSlice(validMissionIDs)...)
     if err != nil {
         return fmt.Errorf("%s: delete targets: %w", op, err)
     }
 
     // Delete the missions
     deleteMissionsQuery := fmt.Sprintf("DELETE FROM missions WHERE id IN (%s)", placeholderString)
-    _, err = tx.Exec(deleteMissionsQuery, int64SliceToInterfaceSlice(validMissionIDs)...)
+    _, err = s.db.ExecContext(ctx, deleteMissionsQuery, int64SliceToInterfaceSlice(validMissionIDs)...)
     if err != nil {
         return fmt.Errorf("%s: delete missions: %w", op, err)
     }
@@ -274,54 +347,70 @@ func int64SliceToInterfaceSlice(slice []int64) []interface{} {
 
 
 
-func (s *Storage) GetAllMissions() ([]common.Mission, error) {
+func (s *Storage) GetAllMissions(ctx context.Context, filter domain.MissionFilter) ([]domain.Mission, error) {
     const op = "storage.sqlite.GetAllMissions"
 
-    rows, err := s.db.Query("SELECT id, cat_id, complete FROM missions")
-    if err != nil {
-        return nil, fmt.Errorf("%s: query: %w", op, err)
-    }
-    defer rows.Close()
-
-    var missions []common.Mission
-    for rows.Next() {
-        var mission common.Mission
-        if err := rows.Scan(&mission.ID, &mission.CatID, &mission.Complete); err != nil {
-            return nil, fmt.Errorf("%s: scan: %w", op, err)
-        }
-
-        targetRows, err := s.db.Query("SELECT id, mission_id, name, country, notes, complete FROM targets WHERE mission_id = ?", mission.ID)
-        if err != nil {
-            return nil, fmt.Errorf("%s: query targets: %w", op, err)
-        }
-        defer targetRows.Close()
+    ctx, cancel := s.opContext(ctx, op)
+    defer cancel()
 
-        var targets []common.Target
-        for targetRows.Next() {
-            var target common.Target
-            if err := targetRows.Scan(&target.ID, &target.MissionID, &target.Name, &target.Country, &target.Notes, &target.Complete); err != nil {
-                return nil, fmt.Errorf("%s: scan target: %w", op, err)
-            }
-            targets = append(targets, target)
+    var w where
+    w.timeRange("created_at", filter.Created)
+    if !filter.Completed.IsZero() {
+        w.add("completed_at IS NOT NULL")
+        w.timeRange("completed_at", filter.Completed)
+    }
+    if filter.Overdue != nil {
+        if *filter.Overdue {
+            w.add(overdueCondition, now())
+        } else {
+            w.add("NOT ("+overdueCondition+")", now())
         }
-        mission.Targets = targets
+    }
+    in(&w, "priority", filter.Priorities)
+    in(&w, "classification", filter.Classifications)
 
-        missions = append(missions, mission)
+    rows, err := s.db.QueryContext(ctx, "SELECT "+missionColumns+" FROM missions"+w.String()+" ORDER BY "+missionOrder(filter.Sort), w.args...)
+    if err != nil {
+        return nil, fmt.Errorf("%s: query: %w", op, err)
     }
 
-    if err := rows.Err(); err != nil {
-        return nil, fmt.Errorf("%s: rows error: %w", op, err)
+    missions, err := s.scanMissionsWithTargets(ctx, rows)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", op, err)
     }
 
     return missions, nil
 }
 
-func (s *Storage) GetMission(id int64) (*common.Mission, error) {
+// missionOrder renders the ORDER BY expressions for sort. Ties are broken
+// by ID and missions without a due time sort last.
+func missionOrder(sort domain.MissionSort) string {
+    dir := ""
+    if sort.Desc {
+        dir = " DESC"
+    }
+
+    switch sort.Field {
+    case domain.SortByPriority:
+        return rank("priority", domain.Priorities) + dir + ", id"
+    case domain.SortByClassification:
+        return rank("classification", domain.Classifications) + dir + ", id"
+    case domain.SortByDueAt:
+        return "due_at IS NULL, due_at" + dir + ", id"
+    case domain.SortByCreatedAt:
+        return "created_at" + dir + ", id"
+    default:
+        return "id" + dir
+    }
+}
+
+func (s *Storage) GetMission(ctx context.Context, id int64) (*domain.Mission, error) {
 const op = "storage.sqlite.GetMissionWithTargets"
 
-	var mission common.Mission
-	err := s.db.QueryRow("SELECT id, cat_id, complete FROM missions WHERE id = ?", id).
-		Scan(&mission.ID, &mission.CatID, &mission.Complete)
+ctx, cancel := s.opContext(ctx, op)
+defer cancel()
+
+	mission, err := scanMission(s.db.QueryRowContext(ctx, "SELECT "+missionColumns+" FROM missions WHERE id = ?", id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -329,23 +418,9 @@ const op = "storage.sqlite.GetMissionWithTargets"
 		return nil, fmt.Errorf("%s: query row: %w", op, err)
 	}
 
-	rows, err := s.db.Query("SELECT id, name, country, notes, complete FROM targets WHERE mission_id = ?", id)
+	mission.Targets, err = s.getTargetsForMission(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("%s: query targets: %w", op, err)
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var target common.Target
-		err := rows.Scan(&target.ID, &target.Name, &target.Country, &target.Notes, &target.Complete)
-		if err != nil {
-			return nil, fmt.Errorf("%s: scan target: %w", op, err)
-		}
-		mission.Targets = append(mission.Targets, target)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return &mission, nil
@@ -353,24 +428,32 @@ const op = "storage.sqlite.GetMissionWithTargets"
 
 
 
-func (s *Storage) isCatAssignedToActiveMission(catID int64) (bool, error) {
-    const op = "storage.sqlite.isCatAssignedToActiveMission"
+// CountActiveMissionsForCat returns how many incomplete missions the cat is
+// currently assigned to.
+func (s *Storage) CountActiveMissionsForCat(ctx context.Context, catID int64) (int, error) {
+    const op = "storage.sqlite.CountActiveMissionsForCat"
 
-    var exists bool
-    err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM missions WHERE cat_id = ? AND complete = 0)", catID).Scan(&exists)
+    ctx, cancel := s.opContext(ctx, op)
+    defer cancel()
+
+    var count int
+    err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM missions WHERE cat_id = ? AND complete = 0", catID).Scan(&count)
     if err != nil {
-        return false, fmt.Errorf("%s: query active mission: %w", op, err)
+        return 0, fmt.Errorf("%s: query active missions: %w", op, err)
     }
 
-    return exists, nil
+    return count, nil
 }
 
 
-func (s *Storage) getTargetCountForMission(missionID int64) (int, error) {
-    const op = "storage.sqlite.getTargetCountForMission"
+func (s *Storage) GetTargetCountForMission(ctx context.Context, missionID int64) (int, error) {
+    const op = "storage.sqlite.GetTargetCountForMission"
+
+    ctx, cancel := s.opContext(ctx, op)
+    defer cancel()
 
     var count int
-    err := s.db.QueryRow("SELECT COUNT(*) FROM targets WHERE mission_id = ?", missionID).Scan(&count)
+    err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM targets WHERE mission_id = ?", missionID).Scan(&count)
     if err != nil {
         return 0, fmt.Errorf("%s: query target count: %w", op, err)
     }
@@ -379,14 +462,121 @@ func (s *Storage) getTargetCountForMission(missionID int64) (int, error) {
 }
 
 
-func (s *Storage) areAllTargetsComplete(missionID int64) (bool, error) {
-	const op = "storage.sqlite.areAllTargetsComplete"
+func (s *Storage) AreAllTargetsComplete(ctx context.Context, missionID int64) (bool, error) {
+	const op = "storage.sqlite.AreAllTargetsComplete"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
 
 	var incompleteCount int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM targets WHERE mission_id = ? AND complete = 0", missionID).Scan(&incompleteCount)
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM targets WHERE mission_id = ? AND complete = 0", missionID).Scan(&incompleteCount)
 	if err != nil {
 		return false, fmt.Errorf("%s: query incomplete targets: %w", op, err)
 	}
 
 	return incompleteCount == 0, nil
 }
+
+// GetMissionsForCat returns a page of the missions a cat has been assigned
+// to, including those since reassigned to another cat, most recently
+// assigned first, together with the total number of such missions.
+func (s *Storage) GetMissionsForCat(ctx context.Context, catID int64, limit, offset int) ([]domain.Mission, int, error) {
+	const op = "storage.sqlite.GetMissionsForCat"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	var total int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(DISTINCT mission_id) FROM mission_assignments WHERE cat_id = ?", catID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: count missions: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+missionColumns+`
+		FROM missions
+		JOIN (
+			SELECT mission_id, MAX(id) AS last_assignment
+			FROM mission_assignments
+			WHERE cat_id = ?
+			GROUP BY mission_id
+		) a ON a.mission_id = missions.id
+		ORDER BY a.last_assignment DESC
+		LIMIT ? OFFSET ?`,
+		catID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: query missions: %w", op, err)
+	}
+
+	missions, err := s.scanMissionsWithTargets(ctx, rows)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return missions, total, nil
+}
+
+// scanMissionsWithTargets reads every mission from rows, closes rows and
+// then loads the targets of each mission.
+func (s *Storage) scanMissionsWithTargets(ctx context.Context, rows *sql.Rows) ([]domain.Mission, error) {
+	defer rows.Close()
+
+	var missions []domain.Mission
+	for rows.Next() {
+		mission, err := scanMission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan mission: %w", err)
+		}
+		missions = append(missions, mission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate missions: %w", err)
+	}
+	rows.Close()
+
+	for i := range missions {
+		targets, err := s.getTargetsForMission(ctx, missions[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		missions[i].Targets = targets
+	}
+
+	return missions, nil
+}
+
+// GetMissionClassifications returns the classification of each of the
+// missions that exist, keyed by mission ID.
+func (s *Storage) GetMissionClassifications(ctx context.Context, missionIDs []int64) (map[int64]domain.Classification, error) {
+	const op = "storage.sqlite.GetMissionClassifications"
+
+	classifications := make(map[int64]domain.Classification, len(missionIDs))
+	if len(missionIDs) == 0 {
+		return classifications, nil
+	}
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	var w where
+	in(&w, "id", missionIDs)
+	rows, err := s.db.QueryContext(ctx, "SELECT id, classification FROM missions"+w.String(), w.args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var classification domain.Classification
+		if err := rows.Scan(&id, &classification); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		classifications[id] = classification
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate: %w", op, err)
+	}
+
+	return classifications, nil
+}
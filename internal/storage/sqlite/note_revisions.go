@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+const noteRevisionColumns = "revision, kind, author, created_at, notes, " + notesColumns
+
+// AddNoteRevision records the next revision in the notes history of a
+// target and returns its number.
+func (s *Storage) AddNoteRevision(ctx context.Context, targetID int64, kind domain.NoteRevisionKind, text, author string) (int, error) {
+	const op = "storage.sqlite.AddNoteRevision"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	sealed, err := s.sealNotes(text)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var revision int
+	err = s.inTx(ctx, func(tx *Storage) error {
+		err := tx.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(revision), 0) + 1 FROM target_note_revisions WHERE target_id = ?", targetID).Scan(&revision)
+		if err != nil {
+			return fmt.Errorf("next revision: %w", err)
+		}
+
+		_, err = tx.db.ExecContext(ctx,
+			"INSERT INTO target_note_revisions (target_id, "+noteRevisionColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			targetID, revision, kind, author, now(), sealed.plain, sealed.keyID, sealed.dataKey, sealed.ciphertext)
+		if err != nil {
+			return fmt.Errorf("insert revision: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revision, nil
+}
+
+// GetNoteRevisions returns the notes history of a target, oldest first.
+func (s *Storage) GetNoteRevisions(ctx context.Context, targetID int64) ([]domain.NoteRevision, error) {
+	const op = "storage.sqlite.GetNoteRevisions"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+noteRevisionColumns+" FROM target_note_revisions WHERE target_id = ? ORDER BY revision", targetID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var revisions []domain.NoteRevision
+	for rows.Next() {
+		r, err := s.scanNoteRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		revisions = append(revisions, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate: %w", op, err)
+	}
+
+	return revisions, nil
+}
+
+func (s *Storage) scanNoteRevision(row rowScanner) (domain.NoteRevision, error) {
+	var r domain.NoteRevision
+	var notes storedNotes
+	if err := row.Scan(&r.Revision, &r.Kind, &r.Author, &r.CreatedAt, &notes.plain, &notes.keyID, &notes.dataKey, &notes.ciphertext); err != nil {
+		return r, err
+	}
+
+	var err error
+	if r.Text, err = s.openNotes(notes); err != nil {
+		return r, fmt.Errorf("revision %d: %w", r.Revision, err)
+	}
+	return r, nil
+}
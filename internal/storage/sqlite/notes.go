@@ -0,0 +1,159 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/envelope"
+)
+
+const (
+	// notesColumns hold sealed notes, next to the plain text notes column.
+	notesColumns = "notes_key_id, notes_data_key, notes_ciphertext"
+	// notesAssignments sets the plain text and the sealed notes columns.
+	notesAssignments = "notes = ?, notes_key_id = ?, notes_data_key = ?, notes_ciphertext = ?"
+)
+
+// storedNotes is how target notes are kept: sealed when keyID is set, and
+// in plain text otherwise.
+type storedNotes struct {
+	plain      string
+	keyID      sql.NullString
+	dataKey    []byte
+	ciphertext []byte
+}
+
+// sealNotes prepares notes for storage, sealing them when encryption is
+// configured.
+func (s *Storage) sealNotes(notes string) (storedNotes, error) {
+	if s.notes == nil {
+		return storedNotes{plain: notes}, nil
+	}
+
+	sealed, err := s.notes.Seal([]byte(notes))
+	if err != nil {
+		return storedNotes{}, fmt.Errorf("seal notes: %w", err)
+	}
+	return storedNotes{
+		keyID:      sql.NullString{String: sealed.KeyID, Valid: true},
+		dataKey:    sealed.DataKey,
+		ciphertext: sealed.Ciphertext,
+	}, nil
+}
+
+// openNotes returns the text of stored notes.
+func (s *Storage) openNotes(n storedNotes) (string, error) {
+	if !n.keyID.Valid {
+		return n.plain, nil
+	}
+	if s.notes == nil {
+		return "", errors.New("notes are encrypted but no encryption key is configured")
+	}
+
+	plaintext, err := s.notes.Open(envelope.Sealed{KeyID: n.keyID.String, DataKey: n.dataKey, Ciphertext: n.ciphertext})
+	if err != nil {
+		return "", fmt.Errorf("open notes: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// sealedTables are the tables holding notes in the columns described by
+// storedNotes.
+var sealedTables = []string{"targets", "target_note_revisions"}
+
+// ReencryptNotes seals all notes, current and past, with the active key,
+// covering notes sealed with an older key and notes still stored in plain
+// text. It returns the number of rows rewritten.
+//
+// Outside a transaction the database is vacuumed and the write-ahead log
+// truncated afterwards, so no copy of the old notes is left in the files.
+func (s *Storage) ReencryptNotes(ctx context.Context) (int, error) {
+	const op = "storage.sqlite.ReencryptNotes"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	if s.notes == nil {
+		return 0, fmt.Errorf("%s: no encryption key is configured", op)
+	}
+
+	var count int
+	err := s.inTx(ctx, func(tx *Storage) error {
+		for _, table := range sealedTables {
+			n, err := tx.reencryptTable(ctx, table)
+			if err != nil {
+				return fmt.Errorf("%s: %w", table, err)
+			}
+			count += n
+		}
+
+		// The search index keeps the words of replaced notes until its
+		// segments are merged.
+		if _, err := tx.db.ExecContext(ctx, "INSERT INTO targets_search (targets_search) VALUES ('optimize')"); err != nil {
+			return fmt.Errorf("optimize search index: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if s.tx == nil {
+		if _, err := s.db.ExecContext(ctx, "VACUUM"); err != nil {
+			return count, fmt.Errorf("%s: vacuum: %w", op, err)
+		}
+		if _, err := s.db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+			return count, fmt.Errorf("%s: checkpoint: %w", op, err)
+		}
+	}
+
+	return count, nil
+}
+
+// reencryptTable seals the notes of every row of table that is not sealed
+// with the active key.
+func (s *Storage) reencryptTable(ctx context.Context, table string) (int, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, notes, "+notesColumns+" FROM "+table+" WHERE notes_key_id IS NULL OR notes_key_id != ? ORDER BY id", s.notes.ActiveKeyID())
+	if err != nil {
+		return 0, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	type pending struct {
+		id    int64
+		notes string
+	}
+	var stale []pending
+	for rows.Next() {
+		var p pending
+		var stored storedNotes
+		if err := rows.Scan(&p.id, &stored.plain, &stored.keyID, &stored.dataKey, &stored.ciphertext); err != nil {
+			return 0, fmt.Errorf("scan: %w", err)
+		}
+		if p.notes, err = s.openNotes(stored); err != nil {
+			return 0, fmt.Errorf("row %d: %w", p.id, err)
+		}
+		stale = append(stale, p)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate: %w", err)
+	}
+	rows.Close()
+
+	for _, p := range stale {
+		sealed, err := s.sealNotes(p.notes)
+		if err != nil {
+			return 0, fmt.Errorf("row %d: %w", p.id, err)
+		}
+		// updated_at is left alone: the notes themselves did not change.
+		_, err = s.db.ExecContext(ctx, "UPDATE "+table+" SET "+notesAssignments+" WHERE id = ?",
+			sealed.plain, sealed.keyID, sealed.dataKey, sealed.ciphertext, p.id)
+		if err != nil {
+			return 0, fmt.Errorf("update row %d: %w", p.id, err)
+		}
+	}
+
+	return len(stale), nil
+}
@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/envelope"
+)
+
+// openWithKeys opens the database at path with a keyring of the given
+// keys, sealing new notes with active.
+func openWithKeys(t *testing.T, path, active string, keys map[string][]byte) *Storage {
+	t.Helper()
+
+	ring, err := envelope.NewKeyring(active, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(path, Options{Notes: ring})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.pool.Close() })
+	return s
+}
+
+func TestNotesAreSealedAndRotated(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "spy-cat.db")
+
+	oldKey, newKey := make([]byte, envelope.KeySize), make([]byte, envelope.KeySize)
+	rand.Read(oldKey)
+	rand.Read(newKey)
+
+	const notes = "hides behind the fish market"
+
+	s := openWithKeys(t, path, "old", map[string][]byte{"old": oldKey})
+	missionID, err := s.CreateMission(ctx, domain.Mission{
+		Priority:       domain.PriorityNormal,
+		Classification: domain.ClassificationPublic,
+		Targets:        []domain.Target{{Name: "Jerry", Country: "UA", Notes: notes}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var plain, keyID string
+	var ciphertext []byte
+	err = s.pool.QueryRow("SELECT notes, notes_key_id, notes_ciphertext FROM targets WHERE mission_id = ?", missionID).
+		Scan(&plain, &keyID, &ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain != "" || keyID != "old" || bytes.Contains(ciphertext, []byte(notes)) {
+		t.Fatalf("notes stored as %q with key %q, want them sealed with the old key", plain, keyID)
+	}
+	s.pool.Close()
+
+	s = openWithKeys(t, path, "new", map[string][]byte{"old": oldKey, "new": newKey})
+	count, err := s.ReencryptNotes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("re-encrypted %d targets, want 1", count)
+	}
+	s.pool.Close()
+
+	// Once rotated, the old key is no longer needed.
+	s = openWithKeys(t, path, "new", map[string][]byte{"new": newKey})
+	mission, err := s.GetMission(ctx, missionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mission.Targets[0].Notes; got != notes {
+		t.Errorf("notes after rotation are %q, want %q", got, notes)
+	}
+}
@@ -0,0 +1,193 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/illiakornyk/spy-cat/internal/countries"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// Column lists shared by every query that loads a whole cat, mission or
+// target, in the order expected by scanCat, scanMission and scanTarget.
+const (
+	catColumns     = "id, name, years_of_experience, breed, breed_id, salary, created_at, updated_at"
+	missionColumns = "id, cat_id, complete, assigned_at, completed_at, created_at, updated_at, starts_at, due_at, priority, classification"
+	targetColumns  = "id, mission_id, name, country, notes, complete, completed_at, created_at, updated_at, due_at, latitude, longitude, address, " + notesColumns
+)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCat(row rowScanner) (domain.SpyCat, error) {
+	var c domain.SpyCat
+	var breedID sql.NullString
+	if err := row.Scan(&c.ID, &c.Name, &c.YearsOfExperience, &c.Breed, &breedID, &c.Salary, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return c, err
+	}
+	c.BreedID = breedID.String
+	return c, nil
+}
+
+func scanMission(row rowScanner) (domain.Mission, error) {
+	var m domain.Mission
+	var assignedAt, completedAt, startsAt, dueAt sql.NullTime
+	if err := row.Scan(&m.ID, &m.CatID, &m.Complete, &assignedAt, &completedAt, &m.CreatedAt, &m.UpdatedAt, &startsAt, &dueAt, &m.Priority, &m.Classification); err != nil {
+		return m, err
+	}
+	m.AssignedAt = timePtr(assignedAt)
+	m.CompletedAt = timePtr(completedAt)
+	m.StartsAt = timePtr(startsAt)
+	m.DueAt = timePtr(dueAt)
+	m.Overdue = m.IsOverdue(now())
+	return m, nil
+}
+
+// scanTarget scans a target and decrypts its notes.
+func (s *Storage) scanTarget(row rowScanner) (domain.Target, error) {
+	var t domain.Target
+	var completedAt, dueAt sql.NullTime
+	var notes storedNotes
+	if err := row.Scan(&t.ID, &t.MissionID, &t.Name, &t.Country, &notes.plain, &t.Complete, &completedAt, &t.CreatedAt, &t.UpdatedAt, &dueAt,
+		&t.Latitude, &t.Longitude, &t.Address, &notes.keyID, &notes.dataKey, &notes.ciphertext); err != nil {
+		return t, err
+	}
+
+	var err error
+	if t.Notes, err = s.openNotes(notes); err != nil {
+		return t, fmt.Errorf("target %d: %w", t.ID, err)
+	}
+	t.CountryName = countries.Name(t.Country)
+	t.CompletedAt = timePtr(completedAt)
+	t.DueAt = timePtr(dueAt)
+	t.Overdue = t.IsOverdue(now())
+	return t, nil
+}
+
+// withColumns scans the columns selected after those row is scanned into
+// by its caller into dest.
+type withColumns struct {
+	row  rowScanner
+	dest []any
+}
+
+func (w withColumns) Scan(dest ...any) error {
+	return w.row.Scan(append(dest, w.dest...)...)
+}
+
+// getTargetsForMission loads the targets of a mission in insertion order.
+func (s *Storage) getTargetsForMission(ctx context.Context, missionID int64) ([]domain.Target, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+targetColumns+" FROM targets WHERE mission_id = ? ORDER BY id", missionID)
+	if err != nil {
+		return nil, fmt.Errorf("query targets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []domain.Target
+	for rows.Next() {
+		target, err := s.scanTarget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan target: %w", err)
+		}
+		targets = append(targets, target)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate targets: %w", err)
+	}
+
+	return targets, nil
+}
+
+// where collects the conditions of a WHERE clause and their arguments.
+type where struct {
+	conditions []string
+	args       []any
+}
+
+func (w *where) add(condition string, args ...any) {
+	w.conditions = append(w.conditions, condition)
+	w.args = append(w.args, args...)
+}
+
+// timeRange adds the bounds of r on column.
+func (w *where) timeRange(column string, r domain.TimeRange) {
+	if r.After != nil {
+		w.add(column+" > ?", r.After.UTC())
+	}
+	if r.Before != nil {
+		w.add(column+" < ?", r.Before.UTC())
+	}
+}
+
+// in adds a condition matching column against any of values. Nothing is
+// added when values is empty.
+func in[T any](w *where, column string, values []T) {
+	if len(values) == 0 {
+		return
+	}
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	w.add(column+" IN ("+placeholders(len(values))+")", args...)
+}
+
+// String renders the clause, including the WHERE keyword, or nothing when
+// there are no conditions.
+func (w *where) String() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+// placeholders returns n comma separated query placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// rank is an SQL expression ordering column by the position of its value
+// in levels rather than alphabetically.
+func rank[T ~string](column string, levels []T) string {
+	var b strings.Builder
+	b.WriteString("CASE " + column)
+	for i, l := range levels {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", l, i)
+	}
+	b.WriteString(" END")
+	return b.String()
+}
+
+// now is the timestamp recorded by writes. Times are stored in UTC.
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// completedAt is the completion time to store for an entity whose complete
+// flag is being set: now when it becomes complete, NULL otherwise.
+func completedAt(complete bool) sql.NullTime {
+	if !complete {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: now(), Valid: true}
+}
+
+// nullTime stores a nil time as NULL.
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,199 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+)
+
+const (
+	// sealedSearchTable is the in-memory full-text index of targets with
+	// their notes opened, used while notes are sealed at rest.
+	sealedSearchTable = "sealed_targets_search"
+	// sealedIndexBatch is how many targets are indexed per transaction. A
+	// search that runs out of time keeps the batches it completed, so
+	// indexing many targets at once spans several searches if need be.
+	sealedIndexBatch = 500
+)
+
+// sealedIndex keeps sealedSearchTable on a connection of its own. The table
+// is held in memory, so opened notes never reach the database files, and it
+// outlives a single search: before each search only the targets that
+// changed since the previous one are opened and indexed again, so the cost
+// of opening every note is paid once, by the first search. It indexes
+// targets of every classification; searches filter by the caller's
+// clearance. Searches that use it run one at a time.
+type sealedIndex struct {
+	mu   sync.Mutex
+	conn *sql.Conn
+	// indexed maps the ID of every indexed target to what it was indexed
+	// with.
+	indexed map[int64]indexedTarget
+}
+
+// indexedTarget identifies the content a target was indexed with. Every
+// seal uses a fresh data key, so the sealed data key changes whenever the
+// notes do.
+type indexedTarget struct {
+	name, country string
+	plain         string
+	keyID         string
+	dataKey       string
+}
+
+// pendingTarget is a target whose entry in the index is out of date.
+type pendingTarget struct {
+	id     int64
+	stored storedNotes
+	indexedTarget
+}
+
+// use brings the index up to date and runs fn on its connection, where
+// sealedSearchTable can be queried next to the database tables.
+func (x *sealedIndex) use(ctx context.Context, s *Storage, fn func(db querier) error) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	err := x.update(ctx, s)
+	if err != nil {
+		err = fmt.Errorf("update sealed notes index: %w", err)
+	} else {
+		err = fn(x.conn)
+	}
+	if err != nil && ctx.Err() == nil {
+		// The connection may be broken; the next search starts afresh.
+		x.reset()
+	}
+	return err
+}
+
+func (x *sealedIndex) update(ctx context.Context, s *Storage) error {
+	if x.conn == nil {
+		if err := x.open(ctx, s.pool); err != nil {
+			return err
+		}
+	}
+
+	rows, err := x.conn.QueryContext(ctx, "SELECT id, name, country, notes, "+notesColumns+" FROM targets")
+	if err != nil {
+		return fmt.Errorf("query targets: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[int64]bool, len(x.indexed))
+	var pending []pendingTarget
+	for rows.Next() {
+		var t pendingTarget
+		if err := rows.Scan(&t.id, &t.name, &t.country, &t.stored.plain, &t.stored.keyID, &t.stored.dataKey, &t.stored.ciphertext); err != nil {
+			return fmt.Errorf("scan target: %w", err)
+		}
+		seen[t.id] = true
+
+		t.plain, t.keyID, t.dataKey = t.stored.plain, t.stored.keyID.String, string(t.stored.dataKey)
+		if x.indexed[t.id] != t.indexedTarget {
+			pending = append(pending, t)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate targets: %w", err)
+	}
+	rows.Close()
+
+	var removed []int64
+	for id := range x.indexed {
+		if !seen[id] {
+			removed = append(removed, id)
+		}
+	}
+	if len(pending) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	for len(removed) > 0 || len(pending) > 0 {
+		n := min(len(pending), sealedIndexBatch)
+		if err := x.apply(ctx, s, removed, pending[:n]); err != nil {
+			return err
+		}
+		removed, pending = nil, pending[n:]
+	}
+	return nil
+}
+
+// apply removes the targets removed from the index and indexes pending in
+// a single transaction.
+func (x *sealedIndex) apply(ctx context.Context, s *Storage, removed []int64, pending []pendingTarget) (err error) {
+	// Only the in-memory table is written, so a deferred transaction takes
+	// no lock on the database file.
+	if _, err := x.conn.ExecContext(ctx, "BEGIN DEFERRED"); err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			x.conn.ExecContext(context.Background(), "ROLLBACK")
+		}
+	}()
+
+	for _, id := range removed {
+		if _, err := x.conn.ExecContext(ctx, "DELETE FROM temp."+sealedSearchTable+" WHERE rowid = ?", id); err != nil {
+			return fmt.Errorf("remove target %d: %w", id, err)
+		}
+	}
+	for _, t := range pending {
+		notes, err := s.openNotes(t.stored)
+		if err != nil {
+			return fmt.Errorf("target %d: %w", t.id, err)
+		}
+		if _, err := x.conn.ExecContext(ctx, "DELETE FROM temp."+sealedSearchTable+" WHERE rowid = ?", t.id); err != nil {
+			return fmt.Errorf("index target %d: %w", t.id, err)
+		}
+		_, err = x.conn.ExecContext(ctx, "INSERT INTO temp."+sealedSearchTable+" (rowid, name, country, notes) VALUES (?, ?, ?, ?)",
+			t.id, t.name, t.country, notes)
+		if err != nil {
+			return fmt.Errorf("index target %d: %w", t.id, err)
+		}
+	}
+	if _, err := x.conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	for _, id := range removed {
+		delete(x.indexed, id)
+	}
+	for _, t := range pending {
+		x.indexed[t.id] = t.indexedTarget
+	}
+	return nil
+}
+
+// open takes a connection out of pool and creates the empty index on it.
+func (x *sealedIndex) open(ctx context.Context, pool *sql.DB) error {
+	conn, err := pool.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("get connection: %w", err)
+	}
+	x.conn, x.indexed = conn, make(map[int64]indexedTarget)
+
+	setup := []string{
+		"PRAGMA temp_store = MEMORY",
+		"CREATE VIRTUAL TABLE temp." + sealedSearchTable + " USING fts5(name, country, notes, tokenize = 'unicode61 remove_diacritics 2')",
+	}
+	for _, stmt := range setup {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create index: %w", err)
+		}
+	}
+	return nil
+}
+
+// reset discards the index. Its connection is closed rather than returned
+// to the pool, so the opened notes go with it.
+func (x *sealedIndex) reset() {
+	if x.conn == nil {
+		return
+	}
+	x.conn.Raw(func(any) error { return driver.ErrBadConn })
+	x.conn.Close()
+	x.conn, x.indexed = nil, nil
+}
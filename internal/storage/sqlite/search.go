@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// Search ranking weighs a match in a name above one in a breed or country,
+// and that above one in notes. Missions rank by their cat and targets alike,
+// below targets matching by name.
+const (
+	catsRank     = "bm25(cats_search, 10.0, 5.0)"
+	missionsRank = "bm25(missions_search, 5.0, 5.0)"
+	// snippetArgs are the arguments of snippet() after the table and
+	// column: markers around matches, the ellipsis and the length in
+	// tokens.
+	snippetArgs = "'<mark>', '</mark>', '…', 16"
+)
+
+func targetsRank(table string) string {
+	return "bm25(" + table + ", 10.0, 5.0, 1.0)"
+}
+
+// Search returns a page of the cats, missions and targets matching query,
+// best matches first, together with the total number of matches.
+//
+// While notes are sealed at rest, targets_search has no notes to match, and
+// indexing them there would store their words in plain text. Targets are
+// then matched against the in-memory index of opened notes instead, which
+// reads the committed state of the database even inside a transaction.
+func (s *Storage) Search(ctx context.Context, query domain.SearchQuery, limit, offset int) ([]domain.SearchHit, int, error) {
+	const op = "storage.sqlite.Search"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	if len(query.Terms) == 0 {
+		return nil, 0, nil
+	}
+
+	var hits []domain.SearchHit
+	var total int
+	var err error
+	if s.sealed != nil && len(query.Classifications) > 0 {
+		err = s.sealed.use(ctx, s, func(db querier) error {
+			hits, total, err = search(ctx, db, sealedSearchTable, query, limit, offset)
+			return err
+		})
+	} else {
+		hits, total, err = search(ctx, s.db, "targets_search", query, limit, offset)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hits, total, nil
+}
+
+// search runs query on db, matching the targets the caller is cleared for
+// against clearedTable.
+func search(ctx context.Context, db querier, clearedTable string, query domain.SearchQuery, limit, offset int) ([]domain.SearchHit, int, error) {
+	catsMatch := matchExpression(query.Terms, "")
+	missionsMatch := matchExpression(query.Terms, "targets")
+	targetsMatch := matchExpression(query.Terms, "country")
+	// Targets the caller may not read only match by name and country.
+	restrictedMatch := "{name country} : (" + targetsMatch + ")"
+
+	var cleared, restricted where
+	if len(query.Classifications) == 0 {
+		cleared.add("0")
+	} else {
+		in(&cleared, "missions.classification", query.Classifications)
+		restricted.add("missions.classification NOT IN ("+placeholders(len(query.Classifications))+")", cleared.args...)
+	}
+	cleared.add(clearedTable+" MATCH ?", targetsMatch)
+	restricted.add("targets_search MATCH ?", restrictedMatch)
+
+	targetHits := func(table string, w where, redacted int) string {
+		return fmt.Sprintf("SELECT '%s' AS type, targets.id AS id, targets.mission_id AS mission_id, targets.name AS name, "+
+			"snippet(%[2]s, -1, %[3]s) AS snippet, %[4]d AS redacted, %[5]s AS rank "+
+			"FROM %[2]s JOIN targets ON targets.id = %[2]s.rowid JOIN missions ON missions.id = targets.mission_id%[6]s",
+			domain.SearchHitTarget, table, snippetArgs, redacted, targetsRank(table), w.String())
+	}
+
+	hits := fmt.Sprintf("SELECT '%s' AS type, rowid AS id, NULL AS mission_id, name, snippet(cats_search, -1, %s) AS snippet, 0 AS redacted, %s AS rank "+
+		"FROM cats_search WHERE cats_search MATCH ?", domain.SearchHitCat, snippetArgs, catsRank) +
+		fmt.Sprintf(" UNION ALL SELECT '%s' AS type, rowid AS id, NULL AS mission_id, '' AS name, snippet(missions_search, -1, %s) AS snippet, 0 AS redacted, %s AS rank "+
+			"FROM missions_search WHERE missions_search MATCH ?", domain.SearchHitMission, snippetArgs, missionsRank) +
+		" UNION ALL " + targetHits(clearedTable, cleared, 0) +
+		" UNION ALL " + targetHits("targets_search", restricted, 1)
+	args := append(append([]any{catsMatch, missionsMatch}, cleared.args...), restricted.args...)
+
+	var total int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+hits+")", args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count hits: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx,
+		"SELECT type, id, mission_id, name, snippet, redacted FROM ("+hits+") ORDER BY rank, type, id LIMIT ? OFFSET ?",
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query hits: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.SearchHit
+	for rows.Next() {
+		var hit domain.SearchHit
+		if err := rows.Scan(&hit.Type, &hit.ID, &hit.MissionID, &hit.Name, &hit.Snippet, &hit.Redacted); err != nil {
+			return nil, 0, fmt.Errorf("scan hit: %w", err)
+		}
+		results = append(results, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate hits: %w", err)
+	}
+
+	return results, total, nil
+}
+
+// matchExpression renders terms as an FTS5 query matching rows that contain
+// every term. Terms are quoted, so no text can change the meaning of the
+// query. With countryColumn set, a term naming a country also matches that
+// column by the country's code.
+func matchExpression(terms []domain.SearchTerm, countryColumn string) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		part := quoteTerm(t.Text)
+		if t.Prefix {
+			part += " *"
+		}
+		if countryColumn != "" && t.Country != "" {
+			part = "(" + part + " OR " + countryColumn + " : " + quoteTerm(t.Country) + ")"
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, " AND ")
+}
+
+func quoteTerm(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
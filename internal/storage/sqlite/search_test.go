@@ -0,0 +1,171 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/envelope"
+)
+
+func TestSearchSealedNotes(t *testing.T) {
+	s := openWithKeys(t, filepath.Join(t.TempDir(), "spy-cat.db"), "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, envelope.KeySize)})
+	ctx := context.Background()
+
+	missionID, err := s.CreateMission(ctx, domain.Mission{
+		Priority:       domain.PriorityNormal,
+		Classification: domain.ClassificationSecret,
+		Targets:        []domain.Target{{Name: "Jerry", Country: "UA", Notes: "hides near the red boat"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var plain string
+	if err := s.pool.QueryRow("SELECT notes FROM targets").Scan(&plain); err != nil || plain != "" {
+		t.Fatalf("notes stored in plain text: %q, %v", plain, err)
+	}
+
+	phrase := domain.SearchQuery{Terms: []domain.SearchTerm{{Text: "red boat"}}}
+
+	phrase.Classifications = []domain.Classification{domain.ClassificationPublic}
+	hits, total, err := s.Search(ctx, phrase, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Errorf("uncleared search found %d hits, want none: %+v", total, hits)
+	}
+
+	phrase.Classifications = []domain.Classification{domain.ClassificationPublic, domain.ClassificationSecret}
+	hits, total, err = s.Search(ctx, phrase, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(hits) != 1 || hits[0].Type != domain.SearchHitTarget || *hits[0].MissionID != missionID {
+		t.Fatalf("cleared search found %d hits %+v, want the target of mission %d", total, hits, missionID)
+	}
+	if want := "hides near the <mark>red boat</mark>"; hits[0].Snippet != want {
+		t.Errorf("snippet = %q, want %q", hits[0].Snippet, want)
+	}
+}
+
+func TestSearchMissions(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	catID, err := s.CreateCat(ctx, "Tom", 3, "Bengal", "beng", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missionID, err := s.CreateMission(ctx, domain.Mission{
+		CatID:          &catID,
+		Priority:       domain.PriorityNormal,
+		Classification: domain.ClassificationPublic,
+		Targets:        []domain.Target{{Name: "Jerry", Country: "UA"}, {Name: "Spike", Country: "PL"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	missions := func(terms ...domain.SearchTerm) []int64 {
+		t.Helper()
+		hits, _, err := s.Search(ctx, domain.SearchQuery{Terms: terms}, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, hit := range hits {
+			if hit.Type == domain.SearchHitMission {
+				ids = append(ids, hit.ID)
+			}
+		}
+		return ids
+	}
+
+	// Terms may match different targets of the mission.
+	if got := missions(domain.SearchTerm{Text: "jerry"}, domain.SearchTerm{Text: "poland", Country: "PL"}); len(got) != 1 || got[0] != missionID {
+		t.Errorf("search by targets found missions %v, want [%d]", got, missionID)
+	}
+	if got := missions(domain.SearchTerm{Text: "tom"}); len(got) != 1 || got[0] != missionID {
+		t.Errorf("search by cat found missions %v, want [%d]", got, missionID)
+	}
+
+	if err := s.DeleteCat(ctx, catID); err != nil {
+		t.Fatal(err)
+	}
+	if got := missions(domain.SearchTerm{Text: "tom"}); len(got) != 0 {
+		t.Errorf("search by deleted cat found missions %v, want none", got)
+	}
+}
+
+func TestSearchSealedNotesFollowsChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spy-cat.db")
+	s := openWithKeys(t, path, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, envelope.KeySize)})
+	ctx := context.Background()
+
+	missionID, err := s.CreateMission(ctx, domain.Mission{
+		Priority:       domain.PriorityNormal,
+		Classification: domain.ClassificationSecret,
+		Targets: []domain.Target{
+			{Name: "Jerry", Country: "UA", Notes: "hides near the red boat"},
+			{Name: "Spike", Country: "PL", Notes: "sleeps in the kennel"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mission, err := s.GetMission(ctx, missionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jerry, spike := mission.Targets[0].ID, mission.Targets[1].ID
+
+	found := func(text string) []int64 {
+		t.Helper()
+		query := domain.SearchQuery{
+			Terms:           []domain.SearchTerm{{Text: text}},
+			Classifications: []domain.Classification{domain.ClassificationPublic, domain.ClassificationSecret},
+		}
+		hits, _, err := s.Search(ctx, query, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, hit := range hits {
+			ids = append(ids, hit.ID)
+		}
+		return ids
+	}
+
+	if got := found("boat"); len(got) != 1 || got[0] != jerry {
+		t.Fatalf("search for boat found %v, want [%d]", got, jerry)
+	}
+
+	// The index outlives the search and only picks up what changed.
+	indexed := s.sealed.indexed[spike]
+	if err := s.UpdateNotes(ctx, jerry, "moved to the harbour"); err != nil {
+		t.Fatal(err)
+	}
+	if got := found("boat"); len(got) != 0 {
+		t.Errorf("search for replaced notes found %v, want none", got)
+	}
+	if got := found("harbour"); len(got) != 1 || got[0] != jerry {
+		t.Errorf("search for new notes found %v, want [%d]", got, jerry)
+	}
+	if s.sealed.indexed[spike] != indexed {
+		t.Error("unchanged target was indexed again")
+	}
+
+	if err := s.DeleteTarget(ctx, spike); err != nil {
+		t.Fatal(err)
+	}
+	if got := found("kennel"); len(got) != 0 {
+		t.Errorf("search for deleted target found %v, want none", got)
+	}
+	if len(s.sealed.indexed) != 1 {
+		t.Errorf("index holds %d targets, want 1", len(s.sealed.indexed))
+	}
+}
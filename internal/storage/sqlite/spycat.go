@@ -1,12 +1,15 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
-	"github.com/illiakornyk/spy-cat/internal/http-server/handlers/spycat"
+	"github.com/illiakornyk/spy-cat/internal/domain"
 )
 
 
@@ -16,16 +19,20 @@ func isConstraintViolation(err error) bool {
 }
 
 
-func (s *Storage) CreateCat(name string, yearsOfExperience int, breed string, salary float64) (int64, error) {
+func (s *Storage) CreateCat(ctx context.Context, name string, yearsOfExperience int, breed, breedID string, salary float64) (int64, error) {
 	const op = "storage.sqlite.SaveCat"
 
-	stmt, err := s.db.Prepare("INSERT INTO spy_cats (name, years_of_experience, breed, salary) VALUES (?, ?, ?, ?)")
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	stmt, err := s.db.PrepareContext(ctx, "INSERT INTO spy_cats (name, years_of_experience, breed, breed_id, salary, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return 0, fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(name, yearsOfExperience, breed, salary)
+	createdAt := now()
+	res, err := stmt.ExecContext(ctx, name, yearsOfExperience, breed, nullString(breedID), salary, createdAt, createdAt)
 	if err != nil {
 		if isConstraintViolation(err) {
 			return 0, fmt.Errorf("%s: %w", op, errors.New("cat already exists"))
@@ -41,74 +48,68 @@ func (s *Storage) CreateCat(name string, yearsOfExperience int, breed string, sa
 	return id, nil
 }
 
-func (s *Storage) DeleteCat(id int64) error {
+func (s *Storage) DeleteCat(ctx context.Context, id int64) error {
     const op = "storage.sqlite.DeleteCat"
 
-    // Begin transaction
-    tx, err := s.db.Begin()
-    if err != nil {
-        return fmt.Errorf("%s: begin transaction: %w", op, err)
-    }
-
-    // Get all missions associated with the cat
-    missions, err := s.getMissionsByCatID(tx, id)
-    if err != nil {
-        tx.Rollback()
-        return fmt.Errorf("%s: get missions by cat ID: %w", op, err)
-    }
-
-
-
-    // Delete each mission within the same transaction
-    err = s.deleteMissionTx(tx, missions, true)
-    if err != nil {
-        tx.Rollback()
-        return fmt.Errorf("%s: delete mission: %w", op, err)
-    }
-
-    // Delete the cat
-    stmt, err := tx.Prepare("DELETE FROM spy_cats WHERE id = ?")
+    ctx, cancel := s.opContext(ctx, op)
+    defer cancel()
+
+    err := s.inTx(ctx, func(tx *Storage) error {
+        // Get all missions associated with the cat
+        missions, err := tx.getMissionsByCatID(ctx, id)
+        if err != nil {
+            return fmt.Errorf("get missions by cat ID: %w", err)
+        }
+
+        // Delete each mission within the same transaction
+        err = tx.deleteMissionTx(ctx, missions, true)
+        if err != nil {
+            return fmt.Errorf("delete mission: %w", err)
+        }
+
+        // Delete the cat
+        stmt, err := tx.db.PrepareContext(ctx, "DELETE FROM spy_cats WHERE id = ?")
+        if err != nil {
+            return fmt.Errorf("prepare statement: %w", err)
+        }
+        defer stmt.Close()
+
+        res, err := stmt.ExecContext(ctx, id)
+        if err != nil {
+            return fmt.Errorf("execute statement: %w", err)
+        }
+
+        rowsAffected, err := res.RowsAffected()
+        if err != nil {
+            return fmt.Errorf("get rows affected: %w", err)
+        }
+
+        if rowsAffected == 0 {
+            return domain.ErrCatNotFound
+        }
+
+        return nil
+    })
     if err != nil {
-        tx.Rollback()
-        return fmt.Errorf("%s: prepare statement: %w", op, err)
-    }
-    defer stmt.Close()
-
-    res, err := stmt.Exec(id)
-    if err != nil {
-        tx.Rollback()
-        return fmt.Errorf("%s: execute statement: %w", op, err)
-    }
-
-    rowsAffected, err := res.RowsAffected()
-    if err != nil {
-        tx.Rollback()
-        return fmt.Errorf("%s: get rows affected: %w", op, err)
-    }
-
-    if rowsAffected == 0 {
-        tx.Rollback()
-        return fmt.Errorf("%s: no cat found with id %d", op, id)
-    }
-
-    // Commit transaction
-    if err := tx.Commit(); err != nil {
-        return fmt.Errorf("%s: commit transaction: %w", op, err)
+        return fmt.Errorf("%s: %w", op, err)
     }
 
     return nil
 }
 
-func (s *Storage) UpdateCatSalary(id int64, salary float64) error {
+func (s *Storage) UpdateCatSalary(ctx context.Context, id int64, salary float64) error {
 	const op = "storage.sqlite.UpdateCatSalary"
 
-	stmt, err := s.db.Prepare("UPDATE spy_cats SET salary = ? WHERE id = ?")
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	stmt, err := s.db.PrepareContext(ctx, "UPDATE spy_cats SET salary = ?, updated_at = ? WHERE id = ?")
 	if err != nil {
 		return fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(salary, id)
+	res, err := stmt.ExecContext(ctx, salary, now(), id)
 	if err != nil {
 		return fmt.Errorf("%s: execute statement: %w", op, err)
 	}
@@ -119,17 +120,20 @@ func (s *Storage) UpdateCatSalary(id int64, salary float64) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("%s: no cat found with id %d", op, id)
+		return fmt.Errorf("%s: %w", op, domain.ErrCatNotFound)
 	}
 
 	return nil
 }
 
-func (s *Storage) CatExists(id int64) (bool, error) {
+func (s *Storage) CatExists(ctx context.Context, id int64) (bool, error) {
 	const op = "storage.sqlite.CatExists"
 
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
 	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM spy_cats WHERE id = ?)", id).Scan(&exists)
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM spy_cats WHERE id = ?)", id).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("%s: query row: %w", op, err)
 	}
@@ -137,19 +141,25 @@ func (s *Storage) CatExists(id int64) (bool, error) {
 	return exists, nil
 }
 
-func (s *Storage) GetAllCats() ([]spycat.SpyCat, error) {
+func (s *Storage) GetAllCats(ctx context.Context, filter domain.CatFilter) ([]domain.SpyCat, error) {
 	const op = "storage.sqlite.GetAllCats"
 
-	rows, err := s.db.Query("SELECT id, name, years_of_experience, breed, salary FROM spy_cats")
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	var w where
+	w.timeRange("created_at", filter.Created)
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+catColumns+" FROM spy_cats"+w.String()+" ORDER BY id", w.args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: query: %w", op, err)
 	}
 	defer rows.Close()
 
-	var cats []spycat.SpyCat
+	var cats []domain.SpyCat
 	for rows.Next() {
-		var cat spycat.SpyCat
-		if err := rows.Scan(&cat.ID, &cat.Name, &cat.YearsOfExperience, &cat.Breed, &cat.Salary); err != nil {
+		cat, err := scanCat(rows)
+		if err != nil {
 			return nil, fmt.Errorf("%s: scan: %w", op, err)
 		}
 		cats = append(cats, cat)
@@ -162,12 +172,13 @@ func (s *Storage) GetAllCats() ([]spycat.SpyCat, error) {
 	return cats, nil
 }
 
-func (s *Storage) GetCatByID(id int64) (*spycat.SpyCat, error) {
+func (s *Storage) GetCatByID(ctx context.Context, id int64) (*domain.SpyCat, error) {
 	const op = "storage.sqlite.GetCatByID"
 
-	var cat spycat.SpyCat
-	err := s.db.QueryRow("SELECT id, name, years_of_experience, breed, salary FROM spy_cats WHERE id = ?", id).
-		Scan(&cat.ID, &cat.Name, &cat.YearsOfExperience, &cat.Breed, &cat.Salary)
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	cat, err := scanCat(s.db.QueryRowContext(ctx, "SELECT "+catColumns+" FROM spy_cats WHERE id = ?", id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -179,10 +190,246 @@ func (s *Storage) GetCatByID(id int64) (*spycat.SpyCat, error) {
 }
 
 
-func (s *Storage) getMissionsByCatID(tx *sql.Tx, catID int64) ([]int64, error) {
+// GetCatTrackRecords summarises the missions of every cat. Cats without
+// missions are included with zero counts.
+func (s *Storage) GetCatTrackRecords(ctx context.Context) ([]domain.CatTrackRecord, error) {
+	const op = "storage.sqlite.GetCatTrackRecords"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.id, COUNT(m.id), COALESCE(SUM(m.complete), 0)
+		FROM spy_cats c
+		LEFT JOIN missions m ON m.cat_id = c.id
+		GROUP BY c.id
+		ORDER BY c.id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query missions: %w", op, err)
+	}
+	defer rows.Close()
+
+	var records []domain.CatTrackRecord
+	index := make(map[int64]int)
+	for rows.Next() {
+		var r domain.CatTrackRecord
+		if err := rows.Scan(&r.CatID, &r.Missions, &r.CompletedMissions); err != nil {
+			return nil, fmt.Errorf("%s: scan missions: %w", op, err)
+		}
+		r.ActiveMissions = r.Missions - r.CompletedMissions
+		r.Countries = []string{}
+		index[r.CatID] = len(records)
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT DISTINCT m.cat_id, t.country
+		FROM missions m
+		JOIN targets t ON t.mission_id = m.id
+		WHERE m.cat_id IS NOT NULL
+		ORDER BY m.cat_id, t.country`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query countries: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var catID int64
+		var country string
+		if err := rows.Scan(&catID, &country); err != nil {
+			return nil, fmt.Errorf("%s: scan countries: %w", op, err)
+		}
+		if i, ok := index[catID]; ok {
+			records[i].Countries = append(records[i].Countries, country)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return records, nil
+}
+
+// GetCatStats summarises the missions a cat has been assigned to. A
+// mission counts as completed by the cat that was assigned to it when it
+// was completed, and a target by the cat assigned to its mission at the
+// time, so stats survive the reassignment of a mission.
+func (s *Storage) GetCatStats(ctx context.Context, catID int64) (*domain.CatStats, error) {
+	const op = "storage.sqlite.GetCatStats"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	stats := &domain.CatStats{
+		CatID:           catID,
+		Countries:       []string{},
+		CurrentMissions: []int64{},
+	}
+
+	assignments, err := s.getAssignmentsForCat(ctx, catID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var total time.Duration
+	var timed int
+	missions := make(map[int64]struct{})
+	for _, a := range assignments {
+		missions[a.missionID] = struct{}{}
+
+		// Only the current assignment can have completed the mission, as
+		// complete missions are not reassigned.
+		if a.unassignedAt.Valid {
+			continue
+		}
+		if !a.missionComplete {
+			stats.CurrentMissions = append(stats.CurrentMissions, a.missionID)
+			continue
+		}
+		stats.MissionsCompleted++
+		if a.assignedAt.Valid && a.missionCompletedAt.Valid && !a.missionCompletedAt.Time.Before(a.assignedAt.Time) {
+			total += a.missionCompletedAt.Time.Sub(a.assignedAt.Time)
+			timed++
+		}
+	}
+	stats.MissionsAssigned = len(missions)
+
+	if timed > 0 {
+		avg := total / time.Duration(timed)
+		seconds := avg.Seconds()
+		stats.AverageTimeToComplete = avg.Round(time.Second).String()
+		stats.AverageTimeToCompleteSeconds = &seconds
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT a.mission_id, a.assigned_at, a.unassigned_at, t.country, t.complete, t.completed_at
+		FROM mission_assignments a
+		JOIN targets t ON t.mission_id = a.mission_id
+		WHERE a.cat_id = ?`, catID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query targets: %w", op, err)
+	}
+	defer rows.Close()
+
+	// The assignments of a cat to a mission never overlap, so a target is
+	// credited at most once.
+	countries := make(map[string]struct{})
+	for rows.Next() {
+		var a assignment
+		var country string
+		var complete bool
+		var completedAt sql.NullTime
+		if err := rows.Scan(&a.missionID, &a.assignedAt, &a.unassignedAt, &country, &complete, &completedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan target: %w", op, err)
+		}
+
+		countries[country] = struct{}{}
+		if complete && a.covers(completedAt) {
+			stats.TargetsCompleted++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	for country := range countries {
+		stats.Countries = append(stats.Countries, country)
+	}
+	slices.Sort(stats.Countries)
+
+	return stats, nil
+}
+
+// assignment is a row of mission_assignments together with the completion
+// state of its mission.
+type assignment struct {
+	missionID          int64
+	assignedAt         sql.NullTime
+	unassignedAt       sql.NullTime
+	missionComplete    bool
+	missionCompletedAt sql.NullTime
+}
+
+// covers reports whether something completed at completedAt was completed
+// while a was the mission's assignment. Completions without a recorded time
+// predate assignment history and are credited to the current assignment.
+func (a assignment) covers(completedAt sql.NullTime) bool {
+	if !completedAt.Valid {
+		return !a.unassignedAt.Valid
+	}
+	if a.assignedAt.Valid && completedAt.Time.Before(a.assignedAt.Time) {
+		return false
+	}
+	return !a.unassignedAt.Valid || completedAt.Time.Before(a.unassignedAt.Time)
+}
+
+func (s *Storage) getAssignmentsForCat(ctx context.Context, catID int64) ([]assignment, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT a.mission_id, a.assigned_at, a.unassigned_at, m.complete, m.completed_at
+		FROM mission_assignments a
+		JOIN missions m ON m.id = a.mission_id
+		WHERE a.cat_id = ?
+		ORDER BY a.mission_id, a.id`, catID)
+	if err != nil {
+		return nil, fmt.Errorf("query assignments: %w", err)
+	}
+	defer rows.Close()
+
+	var assignments []assignment
+	for rows.Next() {
+		var a assignment
+		if err := rows.Scan(&a.missionID, &a.assignedAt, &a.unassignedAt, &a.missionComplete, &a.missionCompletedAt); err != nil {
+			return nil, fmt.Errorf("scan assignment: %w", err)
+		}
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// nullString stores empty strings as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func (s *Storage) GetMissionClassificationsForCat(ctx context.Context, catID int64) ([]domain.Classification, error) {
+	const op = "storage.sqlite.GetMissionClassificationsForCat"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT DISTINCT classification FROM missions WHERE cat_id = ?", catID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query missions: %w", op, err)
+	}
+	defer rows.Close()
+
+	var classifications []domain.Classification
+	for rows.Next() {
+		var classification domain.Classification
+		if err := rows.Scan(&classification); err != nil {
+			return nil, fmt.Errorf("%s: scan classification: %w", op, err)
+		}
+		classifications = append(classifications, classification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows error: %w", op, err)
+	}
+
+	return classifications, nil
+}
+
+func (s *Storage) getMissionsByCatID(ctx context.Context, catID int64) ([]int64, error) {
 	const op = "storage.sqlite.getMissionsByCatID"
 
-	rows, err := tx.Query("SELECT id FROM missions WHERE cat_id = ?", catID)
+	rows, err := s.db.QueryContext(ctx, "SELECT id FROM missions WHERE cat_id = ?", catID)
 	if err != nil {
 		return nil, fmt.Errorf("%s: query missions: %w", op, err)
 	}
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+func TestCatHistorySurvivesReassignment(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	tom, err := s.CreateCat(ctx, "Tom", 3, "Bengal", "beng", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	felix, err := s.CreateCat(ctx, "Felix", 5, "Bengal", "beng", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	missionID, err := s.CreateMission(ctx, domain.Mission{
+		CatID:          &tom,
+		Priority:       domain.PriorityNormal,
+		Classification: domain.ClassificationPublic,
+		Targets: []domain.Target{
+			{Name: "Jerry", Country: "UA"},
+			{Name: "Spike", Country: "PL"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mission, err := s.GetMission(ctx, missionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tom completes the first target, then the mission goes to Felix, who
+	// completes the rest.
+	if err := s.UpdateCompleteStatus(ctx, mission.Targets[0].ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AssignCatToMission(ctx, missionID, felix); err != nil {
+		t.Fatal(err)
+	}
+	// Assigning the same cat again does not split its assignment.
+	if err := s.AssignCatToMission(ctx, missionID, felix); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateCompleteStatus(ctx, mission.Targets[1].ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateMissionCompleteStatus(ctx, missionID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cat := range []int64{tom, felix} {
+		missions, total, err := s.GetMissionsForCat(ctx, cat, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 1 || len(missions) != 1 || missions[0].ID != missionID {
+			t.Errorf("cat %d: missions %v of %d, want mission %d", cat, missions, total, missionID)
+		}
+	}
+
+	tomStats, err := s.GetCatStats(ctx, tom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := domain.CatStats{
+		CatID:            tom,
+		MissionsAssigned: 1,
+		TargetsCompleted: 1,
+		Countries:        []string{"PL", "UA"},
+		CurrentMissions:  []int64{},
+	}
+	if !sameStats(*tomStats, want) {
+		t.Errorf("Tom's stats = %+v, want %+v", *tomStats, want)
+	}
+
+	felixStats, err := s.GetCatStats(ctx, felix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = domain.CatStats{
+		CatID:             felix,
+		MissionsAssigned:  1,
+		MissionsCompleted: 1,
+		TargetsCompleted:  1,
+		Countries:         []string{"PL", "UA"},
+		CurrentMissions:   []int64{},
+	}
+	if !sameStats(*felixStats, want) {
+		t.Errorf("Felix's stats = %+v, want %+v", *felixStats, want)
+	}
+	if felixStats.AverageTimeToCompleteSeconds == nil {
+		t.Error("Felix's stats have no average time to complete")
+	}
+}
+
+// sameStats compares stats without the average completion time, which
+// depends on how long the test took.
+func sameStats(got, want domain.CatStats) bool {
+	return got.CatID == want.CatID &&
+		got.MissionsAssigned == want.MissionsAssigned &&
+		got.MissionsCompleted == want.MissionsCompleted &&
+		got.TargetsCompleted == want.TargetsCompleted &&
+		slices.Equal(got.Countries, want.Countries) &&
+		slices.Equal(got.CurrentMissions, want.CurrentMissions)
+}
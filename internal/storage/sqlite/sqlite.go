@@ -1,23 +1,67 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/illiakornyk/spy-cat/internal/envelope"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/illiakornyk/spy-cat/migrations"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// connectionParams are applied to every pooled connection: foreign keys are
+// enforced, WAL lets readers proceed while a write is in progress and
+// busy_timeout makes concurrent writers wait instead of failing with
+// SQLITE_BUSY. Transactions take the write lock up front, because upgrading
+// a read transaction to a write one fails immediately when another writer
+// holds the lock. secure_delete zeroes deleted content, so rewritten notes
+// do not linger in free pages.
+const connectionParams = "_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate&_secure_delete=on"
+
 type Storage struct {
-    db *sql.DB
+    // pool is the connection pool; db is the pool itself or, inside WithTx,
+    // the open transaction every query must go through.
+    pool     *sql.DB
+    db       querier
+    tx       *sql.Tx
+    depth    int
+    timeouts Timeouts
+    notes    *envelope.Keyring
+    // sealed indexes opened notes for searching while notes are sealed.
+    sealed   *sealedIndex
+}
+
+type Options struct {
+    // MigrationsPath overrides the migrations embedded in the binary with a
+    // directory on disk, which is handy while writing new migrations.
+    MigrationsPath string
+    Timeouts       Timeouts
+    // Notes encrypts target notes at rest. When nil, notes are stored in
+    // plain text.
+    Notes          *envelope.Keyring
+}
+
+// Timeouts bounds how long a single storage operation may run. Operations
+// are keyed by their op name, e.g. "storage.sqlite.GetAllMissions"; any
+// operation without an entry uses Default. A zero duration means the
+// operation is only bounded by the caller's context.
+type Timeouts struct {
+    Default    time.Duration
+    Operations map[string]time.Duration
 }
 
-func New(storagePath string) (*Storage, error) {
+// New opens the database at storagePath and applies pending migrations.
+func New(storagePath string, opts Options) (*Storage, error) {
     const op = "storage.sqlite.NewStorage"
 
     log.Printf("Opening SQLite database at path: %s", storagePath)
@@ -27,26 +71,93 @@ func New(storagePath string) (*Storage, error) {
         return nil, fmt.Errorf("%s: %w", op, err)
     }
 
-    db, err := sql.Open("sqlite3", storagePath)
+    if err := runMigrations(storagePath, opts.MigrationsPath); err != nil {
+        return nil, fmt.Errorf("%s: %w", op, err)
+    }
+
+    db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?%s", storagePath, connectionParams))
     if err != nil {
         return nil, fmt.Errorf("%s: %w", op, err)
     }
 
-    if err := runMigrations(db); err != nil {
+    if err := db.Ping(); err != nil {
         return nil, fmt.Errorf("%s: %w", op, err)
     }
 
-    return &Storage{db: db}, nil
+    s := &Storage{pool: db, db: db, timeouts: opts.Timeouts, notes: opts.Notes}
+    if opts.Notes != nil {
+        s.sealed = &sealedIndex{}
+    }
+    return s, nil
+}
+
+// opContext derives the context a single operation runs under, applying the
+// deadline configured for op.
+func (s *Storage) opContext(ctx context.Context, op string) (context.Context, context.CancelFunc) {
+    timeout, ok := s.timeouts.Operations[op]
+    if !ok {
+        timeout = s.timeouts.Default
+    }
+    if timeout <= 0 {
+        return context.WithCancel(ctx)
+    }
+    return context.WithTimeout(ctx, timeout)
 }
 
-func runMigrations(db *sql.DB) error {
+// MigrationVersion reports the schema version currently applied to the
+// database and whether the last migration left it in a dirty state.
+func (s *Storage) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	const op = "storage.sqlite.MigrationVersion"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	var version uint
+	var dirty bool
+	err := s.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("%s: query version: %w", op, err)
+	}
+
+	return version, dirty, nil
+}
+
+func migrationSource(migrationsPath string) fs.FS {
+	if migrationsPath != "" {
+		return os.DirFS(migrationsPath)
+	}
+	return migrations.FS
+}
+
+// runMigrations applies migrations over a dedicated connection that does not
+// enforce foreign keys, since rebuilding a table referenced by others is only
+// possible with enforcement switched off.
+func runMigrations(storagePath, migrationsPath string) error {
+    db, err := sql.Open("sqlite3", storagePath)
+    if err != nil {
+        return fmt.Errorf("could not open database for migration: %w", err)
+    }
+    defer db.Close()
+
+    if err := checkFTS5(db); err != nil {
+        return err
+    }
+
     driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
     if err != nil {
         return fmt.Errorf("could not create migration driver: %w", err)
     }
 
-    m, err := migrate.NewWithDatabaseInstance(
-        "file://migrations",
+    source, err := iofs.New(migrationSource(migrationsPath), ".")
+    if err != nil {
+        return fmt.Errorf("could not open migration source: %w", err)
+    }
+
+    m, err := migrate.NewWithInstance(
+        "iofs", source,
         "sqlite3", driver)
     if err != nil {
         return fmt.Errorf("could not start migration: %w", err)
@@ -58,3 +169,20 @@ func runMigrations(db *sql.DB) error {
 
     return nil
 }
+
+// ErrNoFTS5 is returned by New when SQLite was compiled without FTS5, which
+// the search index needs. go-sqlite3 only includes it when built with the
+// sqlite_fts5 tag.
+var ErrNoFTS5 = errors.New("SQLite was built without FTS5 support, build with -tags sqlite_fts5")
+
+// checkFTS5 fails with ErrNoFTS5 unless SQLite was compiled with FTS5.
+func checkFTS5(db *sql.DB) error {
+	var enabled bool
+	if err := db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled); err != nil {
+		return fmt.Errorf("could not check for FTS5 support: %w", err)
+	}
+	if !enabled {
+		return ErrNoFTS5
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// newTestStorage opens a fresh, fully migrated database.
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+
+	s, err := New(filepath.Join(t.TempDir(), "spy-cat.db"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.pool.Close() })
+	return s
+}
@@ -1,53 +1,65 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
-	"github.com/illiakornyk/spy-cat/internal/common"
+	"github.com/illiakornyk/spy-cat/internal/domain"
 )
 
-func (s *Storage) UpdateTarget(id int64, target common.Target) error {
+func (s *Storage) UpdateTarget(ctx context.Context, id int64, target domain.Target) error {
 	const op = "storage.sqlite.UpdateTarget"
 
-	var complete bool
-	err := s.db.QueryRow("SELECT complete FROM targets WHERE id = ?", id).Scan(&complete)
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	notes, err := s.sealNotes(target.Notes)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("%s: target not found", op)
-		}
-		return fmt.Errorf("%s: query target: %w", op, err)
-	}
-	if complete {
-		return fmt.Errorf("%s: cannot update a completed target", op)
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err := s.db.Prepare("UPDATE targets SET name = ?, country = ?, notes = ?, complete = ? WHERE id = ?")
+	stmt, err := s.db.PrepareContext(ctx, "UPDATE targets SET name = ?, country = ?, latitude = ?, longitude = ?, address = ?, "+notesAssignments+", complete = ?, completed_at = CASE WHEN ? THEN COALESCE(completed_at, ?) END, updated_at = ? WHERE id = ?")
 	if err != nil {
 		return fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(target.Name, target.Country, target.Notes, target.Complete, id)
+	updatedAt := now()
+	res, err := stmt.ExecContext(ctx, target.Name, target.Country, target.Latitude, target.Longitude, target.Address, notes.plain, notes.keyID, notes.dataKey, notes.ciphertext, target.Complete, target.Complete, updatedAt, updatedAt, id)
 	if err != nil {
 		return fmt.Errorf("%s: execute statement: %w", op, err)
 	}
 
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrTargetNotFound)
+	}
+
 	return nil
 }
 
 
-func (s *Storage) UpdateCompleteStatus(targetID int64, complete bool) error {
+func (s *Storage) UpdateCompleteStatus(ctx context.Context, targetID int64, complete bool) error {
 	const op = "storage.sqlite.UpdateCompleteStatus"
 
-	// Update the complete status
-	stmt, err := s.db.Prepare("UPDATE targets SET complete = ? WHERE id = ?")
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	// Update the complete status, keeping the original completion time when
+	// a complete target is marked complete again
+	stmt, err := s.db.PrepareContext(ctx, "UPDATE targets SET complete = ?, completed_at = CASE WHEN ? THEN COALESCE(completed_at, ?) END, updated_at = ? WHERE id = ?")
 	if err != nil {
 		return fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(complete, targetID)
+	updatedAt := now()
+	_, err = stmt.ExecContext(ctx, complete, complete, updatedAt, updatedAt, targetID)
 	if err != nil {
 		return fmt.Errorf("%s: execute statement: %w", op, err)
 	}
@@ -55,41 +67,66 @@ func (s *Storage) UpdateCompleteStatus(targetID int64, complete bool) error {
 	return nil
 }
 
-func (s *Storage) UpdateNotes(targetID int64, notes string) error {
+func (s *Storage) UpdateNotes(ctx context.Context, targetID int64, notes string) error {
 	const op = "storage.sqlite.UpdateNotes"
 
-	var targetComplete, missionComplete bool
-	err := s.db.QueryRow("SELECT t.complete, m.complete FROM targets t JOIN missions m ON t.mission_id = m.id WHERE t.id = ?", targetID).Scan(&targetComplete, &missionComplete)
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	sealed, err := s.sealNotes(notes)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("%s: target not found", op)
-		}
-		return fmt.Errorf("%s: query target and mission: %w", op, err)
-	}
-	if targetComplete || missionComplete {
-		return fmt.Errorf("%s: cannot update notes for a completed target or mission", op)
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err := s.db.Prepare("UPDATE targets SET notes = ? WHERE id = ?")
+	stmt, err := s.db.PrepareContext(ctx, "UPDATE targets SET "+notesAssignments+", updated_at = ? WHERE id = ?")
 	if err != nil {
 		return fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(notes, targetID)
+	res, err := stmt.ExecContext(ctx, sealed.plain, sealed.keyID, sealed.dataKey, sealed.ciphertext, now(), targetID)
 	if err != nil {
 		return fmt.Errorf("%s: execute statement: %w", op, err)
 	}
 
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrTargetNotFound)
+	}
+
 	return nil
 }
 
+func (s *Storage) GetTarget(ctx context.Context, targetID int64) (*domain.Target, error) {
+	const op = "storage.sqlite.GetTarget"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	target, err := s.scanTarget(s.db.QueryRowContext(ctx, "SELECT "+targetColumns+" FROM targets WHERE id = ?", targetID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: query row: %w", op, err)
+	}
+
+	return &target, nil
+}
+
 
-func (s *Storage) TargetExists(targetID int64) (bool, error) {
+func (s *Storage) TargetExists(ctx context.Context, targetID int64) (bool, error) {
 	const op = "storage.sqlite.TargetExists"
 
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
 	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM targets WHERE id = ?)", targetID).Scan(&exists)
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM targets WHERE id = ?)", targetID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("%s: query row: %w", op, err)
 	}
@@ -99,67 +136,58 @@ func (s *Storage) TargetExists(targetID int64) (bool, error) {
 
 
 
-func (s *Storage) DeleteTarget(targetID int64) error {
+func (s *Storage) DeleteTarget(ctx context.Context, targetID int64) error {
 	const op = "storage.sqlite.DeleteTarget"
 
-	var complete bool
-	err := s.db.QueryRow("SELECT complete FROM targets WHERE id = ?", targetID).Scan(&complete)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("%s: target not found", op)
-		}
-		return fmt.Errorf("%s: query target: %w", op, err)
-	}
-	if complete {
-		return fmt.Errorf("%s: cannot delete a completed target", op)
-	}
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
 
-	stmt, err := s.db.Prepare("DELETE FROM targets WHERE id = ?")
+	stmt, err := s.db.PrepareContext(ctx, "DELETE FROM targets WHERE id = ?")
 	if err != nil {
 		return fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(targetID)
+	res, err := stmt.ExecContext(ctx, targetID)
 	if err != nil {
 		return fmt.Errorf("%s: execute statement: %w", op, err)
 	}
 
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrTargetNotFound)
+	}
+
 	return nil
 }
 
 
-func (s *Storage) AddTarget(missionID int64, name, country, notes string) (int64, error) {
+// AddTarget inserts an incomplete target into the mission. Only the name,
+// country, notes, deadline and location of target are used.
+func (s *Storage) AddTarget(ctx context.Context, missionID int64, target domain.Target) (int64, error) {
     const op = "storage.sqlite.AddTarget"
 
-    var complete bool
-    err := s.db.QueryRow("SELECT complete FROM missions WHERE id = ?", missionID).Scan(&complete)
-    if err != nil {
-        if err == sql.ErrNoRows {
-            return 0, fmt.Errorf("%s: mission not found", op)
-        }
-        return 0, fmt.Errorf("%s: query mission: %w", op, err)
-    }
-    if complete {
-        return 0, fmt.Errorf("%s: cannot add target to a completed mission", op)
-    }
+    ctx, cancel := s.opContext(ctx, op)
+    defer cancel()
 
-    // Check the current number of targets in the mission
-    count, err := s.getTargetCountForMission(missionID)
+    notes, err := s.sealNotes(target.Notes)
     if err != nil {
         return 0, fmt.Errorf("%s: %w", op, err)
     }
-    if count >= 3 {
-        return 0, fmt.Errorf("%s: mission already has the maximum number of targets (3)", op)
-    }
 
-    stmt, err := s.db.Prepare("INSERT INTO targets (mission_id, name, country, notes, complete) VALUES (?, ?, ?, ?, 0)")
+    stmt, err := s.db.PrepareContext(ctx, "INSERT INTO targets (mission_id, name, country, notes, complete, created_at, updated_at, due_at, latitude, longitude, address, "+notesColumns+") VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
     if err != nil {
         return 0, fmt.Errorf("%s: prepare statement: %w", op, err)
     }
     defer stmt.Close()
 
-    res, err := stmt.Exec(missionID, name, country, notes)
+    createdAt := now()
+    res, err := stmt.ExecContext(ctx, missionID, target.Name, target.Country, notes.plain, createdAt, createdAt, nullTime(target.DueAt),
+        target.Latitude, target.Longitude, target.Address, notes.keyID, notes.dataKey, notes.ciphertext)
     if err != nil {
         return 0, fmt.Errorf("%s: execute statement: %w", op, err)
     }
@@ -171,3 +199,114 @@ func (s *Storage) AddTarget(missionID int64, name, country, notes string) (int64
 
     return targetID, nil
 }
+
+// GetTargetsInArea returns the targets located inside area, with the cat
+// assigned to the mission of each, in no particular order.
+func (s *Storage) GetTargetsInArea(ctx context.Context, area domain.TargetArea) ([]domain.NearbyTarget, error) {
+	const op = "storage.sqlite.GetTargetsInArea"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	if len(area.Classifications) == 0 {
+		return nil, nil
+	}
+
+	var w where
+	w.add("latitude BETWEEN ? AND ?", area.MinLat, area.MaxLat)
+	if area.MinLon > area.MaxLon {
+		w.add("(longitude >= ? OR longitude <= ?)", area.MinLon, area.MaxLon)
+	} else {
+		w.add("longitude BETWEEN ? AND ?", area.MinLon, area.MaxLon)
+	}
+
+	var missions where
+	in(&missions, "classification", area.Classifications)
+	w.add("mission_id IN (SELECT id FROM missions"+missions.String()+")", missions.args...)
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+targetColumns+", (SELECT cat_id FROM missions WHERE missions.id = targets.mission_id) FROM targets"+w.String(), w.args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var targets []domain.NearbyTarget
+	for rows.Next() {
+		var nearby domain.NearbyTarget
+		nearby.Target, err = s.scanTarget(withColumns{row: rows, dest: []any{&nearby.CatID}})
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		targets = append(targets, nearby)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate: %w", op, err)
+	}
+
+	return targets, nil
+}
+
+// GetTargets returns a page of the targets matching filter, in insertion
+// order, together with the total number of matching targets.
+func (s *Storage) GetTargets(ctx context.Context, filter domain.TargetFilter, limit, offset int) ([]domain.Target, int, error) {
+	const op = "storage.sqlite.GetTargets"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	var w where
+	w.timeRange("created_at", filter.Created)
+	in(&w, "country", filter.Countries)
+	if filter.Complete != nil {
+		w.add("complete = ?", *filter.Complete)
+	}
+	if filter.MissionID != nil {
+		w.add("mission_id = ?", *filter.MissionID)
+	}
+	if filter.CatID != nil {
+		w.add("mission_id IN (SELECT id FROM missions WHERE cat_id = ?)", *filter.CatID)
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM targets"+w.String(), w.args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%s: count targets: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+targetColumns+" FROM targets"+w.String()+" ORDER BY id LIMIT ? OFFSET ?",
+		append(w.args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: query targets: %w", op, err)
+	}
+	defer rows.Close()
+
+	var targets []domain.Target
+	for rows.Next() {
+		target, err := s.scanTarget(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: scan target: %w", op, err)
+		}
+		targets = append(targets, target)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: iterate targets: %w", op, err)
+	}
+
+	return targets, total, nil
+}
+
+// MoveTarget transfers a target to another mission.
+func (s *Storage) MoveTarget(ctx context.Context, targetID, missionID int64) error {
+	const op = "storage.sqlite.MoveTarget"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, "UPDATE targets SET mission_id = ?, updated_at = ? WHERE id = ?", missionID, now(), targetID)
+	if err != nil {
+		return fmt.Errorf("%s: update target: %w", op, err)
+	}
+
+	return nil
+}
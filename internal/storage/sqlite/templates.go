@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/countries"
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+const templateColumns = "id, name, priority, classification, created_by, created_at, updated_at"
+
+// CreateMissionTemplate stores a template with its targets and returns its
+// ID. It fails with ErrTemplateExists when the name is taken.
+func (s *Storage) CreateMissionTemplate(ctx context.Context, template domain.MissionTemplate) (int64, error) {
+	const op = "storage.sqlite.CreateMissionTemplate"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	var id int64
+	err := s.inTx(ctx, func(tx *Storage) error {
+		createdAt := now()
+		res, err := tx.db.ExecContext(ctx,
+			"INSERT INTO mission_templates (name, priority, classification, created_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+			template.Name, template.Priority, template.Classification, template.CreatedBy, createdAt, createdAt)
+		if err != nil {
+			if isConstraintViolation(err) {
+				return domain.ErrTemplateExists
+			}
+			return fmt.Errorf("insert template: %w", err)
+		}
+
+		id, err = res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+
+		for _, t := range template.Targets {
+			_, err := tx.db.ExecContext(ctx,
+				"INSERT INTO mission_template_targets (template_id, name, country, notes) VALUES (?, ?, ?, ?)",
+				id, t.Name, t.Country, t.Notes)
+			if err != nil {
+				return fmt.Errorf("insert target: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetMissionTemplate returns a template with its targets, or nil when there
+// is none with the given ID.
+func (s *Storage) GetMissionTemplate(ctx context.Context, id int64) (*domain.MissionTemplate, error) {
+	const op = "storage.sqlite.GetMissionTemplate"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	template, err := scanTemplate(s.db.QueryRowContext(ctx, "SELECT "+templateColumns+" FROM mission_templates WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: scan template: %w", op, err)
+	}
+
+	if template.Targets, err = s.getTemplateTargets(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &template, nil
+}
+
+// GetMissionTemplates returns every template with its targets, ordered by
+// name.
+func (s *Storage) GetMissionTemplates(ctx context.Context) ([]domain.MissionTemplate, error) {
+	const op = "storage.sqlite.GetMissionTemplates"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+templateColumns+" FROM mission_templates ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("%s: query templates: %w", op, err)
+	}
+	defer rows.Close()
+
+	var templates []domain.MissionTemplate
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan template: %w", op, err)
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate templates: %w", op, err)
+	}
+	rows.Close()
+
+	for i := range templates {
+		if templates[i].Targets, err = s.getTemplateTargets(ctx, templates[i].ID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return templates, nil
+}
+
+// DeleteMissionTemplate removes a template and its targets. Missions
+// created from it are left alone.
+func (s *Storage) DeleteMissionTemplate(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DeleteMissionTemplate"
+
+	ctx, cancel := s.opContext(ctx, op)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM mission_templates WHERE id = ?", id); err != nil {
+		return fmt.Errorf("%s: delete template: %w", op, err)
+	}
+
+	return nil
+}
+
+func scanTemplate(row rowScanner) (domain.MissionTemplate, error) {
+	var t domain.MissionTemplate
+	err := row.Scan(&t.ID, &t.Name, &t.Priority, &t.Classification, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+// getTemplateTargets loads the targets of a template in insertion order.
+func (s *Storage) getTemplateTargets(ctx context.Context, templateID int64) ([]domain.TemplateTarget, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT name, country, notes FROM mission_template_targets WHERE template_id = ? ORDER BY id", templateID)
+	if err != nil {
+		return nil, fmt.Errorf("query template targets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []domain.TemplateTarget
+	for rows.Next() {
+		var t domain.TemplateTarget
+		if err := rows.Scan(&t.Name, &t.Country, &t.Notes); err != nil {
+			return nil, fmt.Errorf("scan template target: %w", err)
+		}
+		t.CountryName = countries.Name(t.Country)
+		targets = append(targets, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate template targets: %w", err)
+	}
+
+	return targets, nil
+}
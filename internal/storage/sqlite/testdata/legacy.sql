@@ -0,0 +1,27 @@
+-- Rows as the schema before the schema-constraints migration allowed them.
+INSERT INTO spy_cats (id, name, years_of_experience, breed, salary) VALUES
+    (1, 'Tom', 3, 'Bengal', 1000),
+    (2, 'Felix', NULL, NULL, 500),
+    (3, 'Unpaid', 2, 'Bengal', NULL),
+    (4, 'Volunteer', 1, 'Bengal', 0),
+    (5, '', 1, 'Bengal', 100),
+    (6, 'Retired', 9, 'Bengal', 100);
+
+DELETE FROM spy_cats WHERE id = 6;
+
+INSERT INTO missions (id, cat_id, complete) VALUES
+    (1, 1, 0),
+    (2, NULL, 1),
+    (3, NULL, 1);
+
+DELETE FROM missions WHERE id = 3;
+
+INSERT INTO targets (id, mission_id, name, country, notes, complete) VALUES
+    (1, 1, 'Jerry', 'UA', 'in the kitchen', 0),
+    (2, 1, 'Spike', 'PL', NULL, NULL),
+    (3, NULL, 'Stray', 'UA', '', 0),
+    (4, 2, NULL, 'UA', '', 0),
+    (5, 2, 'Nowhere', NULL, '', 0),
+    (6, 2, 'Caught', 'UA', '', 1);
+
+DELETE FROM targets WHERE id = 6;
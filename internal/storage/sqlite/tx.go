@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+// querier is implemented by both *sql.DB and *sql.Tx, so every storage
+// method runs unchanged inside or outside a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+var _ storage.Store = (*Storage)(nil)
+
+// WithTx runs fn against a Storage bound to a new transaction, or to a
+// savepoint when s is already inside one.
+func (s *Storage) WithTx(ctx context.Context, fn func(tx storage.Store) error) error {
+	return s.inTx(ctx, func(tx *Storage) error {
+		return fn(tx)
+	})
+}
+
+// inTx is WithTx for callers inside this package that need the concrete
+// *Storage to reach unexported helpers.
+func (s *Storage) inTx(ctx context.Context, fn func(tx *Storage) error) error {
+	const op = "storage.sqlite.inTx"
+
+	if s.tx != nil {
+		return s.withSavepoint(ctx, fn)
+	}
+
+	tx, err := s.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+
+	txStorage := &Storage{pool: s.pool, db: tx, tx: tx, timeouts: s.timeouts, notes: s.notes, sealed: s.sealed}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(txStorage); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) withSavepoint(ctx context.Context, fn func(tx *Storage) error) error {
+	const op = "storage.sqlite.withSavepoint"
+
+	depth := s.depth + 1
+	name := fmt.Sprintf("sp_%d", depth)
+
+	if _, err := s.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("%s: create savepoint: %w", op, err)
+	}
+
+	nested := &Storage{pool: s.pool, db: s.tx, tx: s.tx, depth: depth, timeouts: s.timeouts, notes: s.notes, sealed: s.sealed}
+
+	// Rolling back to a savepoint keeps it open, so it is released on every
+	// path to leave the enclosing transaction in a clean state. The caller's
+	// context may already be done at this point, which must not prevent the
+	// rollback.
+	rollback := func() {
+		s.tx.ExecContext(context.Background(), "ROLLBACK TO "+name)
+		s.tx.ExecContext(context.Background(), "RELEASE "+name)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(nested); err != nil {
+		rollback()
+		return err
+	}
+
+	if _, err := s.tx.ExecContext(ctx, "RELEASE "+name); err != nil {
+		return fmt.Errorf("%s: release savepoint: %w", op, err)
+	}
+
+	return nil
+}
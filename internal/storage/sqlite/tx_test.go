@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+	"github.com/illiakornyk/spy-cat/internal/storage"
+)
+
+func catNames(t *testing.T, s *Storage) []string {
+	t.Helper()
+
+	cats, err := s.GetAllCats(context.Background(), domain.CatFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(cats))
+	for _, c := range cats {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func createCat(t *testing.T, s storage.Store, name string) {
+	t.Helper()
+
+	if _, err := s.CreateCat(context.Background(), name, 1, "Bengal", "beng", 100); err != nil {
+		t.Fatalf("CreateCat %s: %v", name, err)
+	}
+}
+
+func TestWithTxCommits(t *testing.T) {
+	s := newTestStorage(t)
+
+	err := s.WithTx(context.Background(), func(tx storage.Store) error {
+		createCat(t, tx, "Tom")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := catNames(t, s); !slices.Equal(got, []string{"Tom"}) {
+		t.Errorf("cats = %v, want [Tom]", got)
+	}
+}
+
+func TestWithTxRollsBackOnError(t *testing.T) {
+	s := newTestStorage(t)
+	errFailed := errors.New("failed")
+
+	err := s.WithTx(context.Background(), func(tx storage.Store) error {
+		createCat(t, tx, "Tom")
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("WithTx = %v, want %v", err, errFailed)
+	}
+
+	if got := catNames(t, s); len(got) != 0 {
+		t.Errorf("cats = %v, want none", got)
+	}
+}
+
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+	s := newTestStorage(t)
+
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("recovered %v, want the panic to propagate", p)
+			}
+		}()
+		s.WithTx(context.Background(), func(tx storage.Store) error {
+			createCat(t, tx, "Tom")
+			panic("boom")
+		})
+	}()
+
+	if got := catNames(t, s); len(got) != 0 {
+		t.Errorf("cats = %v, want none", got)
+	}
+	// The connection went back to the pool without an open transaction.
+	createCat(t, s, "Felix")
+}
+
+func TestWithTxNestedRollbackKeepsOuter(t *testing.T) {
+	s := newTestStorage(t)
+	errFailed := errors.New("failed")
+
+	err := s.WithTx(context.Background(), func(tx storage.Store) error {
+		createCat(t, tx, "Tom")
+
+		err := tx.WithTx(context.Background(), func(inner storage.Store) error {
+			createCat(t, inner, "Felix")
+			return errFailed
+		})
+		if !errors.Is(err, errFailed) {
+			t.Errorf("inner WithTx = %v, want %v", err, errFailed)
+		}
+
+		return tx.WithTx(context.Background(), func(inner storage.Store) error {
+			createCat(t, inner, "Garfield")
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := catNames(t, s); !slices.Equal(got, []string{"Tom", "Garfield"}) {
+		t.Errorf("cats = %v, want [Tom Garfield]", got)
+	}
+}
+
+func TestWithTxNestedPanicRollsBackEverything(t *testing.T) {
+	s := newTestStorage(t)
+
+	func() {
+		defer func() { recover() }()
+		s.WithTx(context.Background(), func(tx storage.Store) error {
+			createCat(t, tx, "Tom")
+			return tx.WithTx(context.Background(), func(inner storage.Store) error {
+				createCat(t, inner, "Felix")
+				panic("boom")
+			})
+		})
+	}()
+
+	if got := catNames(t, s); len(got) != 0 {
+		t.Errorf("cats = %v, want none", got)
+	}
+}
+
+func TestWithTxCanceledContext(t *testing.T) {
+	s := newTestStorage(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := s.WithTx(ctx, func(tx storage.Store) error {
+		t.Error("fn ran with a canceled context")
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WithTx with a canceled context = %v, want %v", err, context.Canceled)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	err = s.WithTx(ctx, func(tx storage.Store) error {
+		createCat(t, tx, "Tom")
+		cancel()
+		return nil
+	})
+	if err == nil {
+		t.Error("WithTx committed after its context was canceled")
+	}
+
+	if got := catNames(t, s); len(got) != 0 {
+		t.Errorf("cats = %v, want none", got)
+	}
+}
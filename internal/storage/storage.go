@@ -1,8 +1,116 @@
 package storage
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
 
 var (
     ErrURLNotFound = errors.New("url not found")
     ErrURLExists   = errors.New("url exists")
+
+    ErrBlobNotFound = errors.New("blob not found")
 )
+
+// Store is the full set of storage operations. Every implementation must be
+// usable both directly and inside WithTx, where the Store handed to fn runs
+// all of its operations in the same transaction.
+type Store interface {
+	// WithTx runs fn in a transaction that is committed when fn returns nil
+	// and rolled back when it returns an error or panics. Calling WithTx on
+	// a Store that is already inside a transaction opens a savepoint, so
+	// operations compose without knowing whether a transaction is open.
+	WithTx(ctx context.Context, fn func(tx Store) error) error
+
+	CreateCat(ctx context.Context, name string, yearsOfExperience int, breed, breedID string, salary float64) (int64, error)
+	GetCatByID(ctx context.Context, id int64) (*domain.SpyCat, error)
+	GetAllCats(ctx context.Context, filter domain.CatFilter) ([]domain.SpyCat, error)
+	CatExists(ctx context.Context, id int64) (bool, error)
+	UpdateCatSalary(ctx context.Context, id int64, salary float64) error
+	DeleteCat(ctx context.Context, id int64) error
+	// GetMissionClassificationsForCat returns the classification of every
+	// mission currently assigned to the cat, which DeleteCat deletes too.
+	GetMissionClassificationsForCat(ctx context.Context, catID int64) ([]domain.Classification, error)
+	GetCatTrackRecords(ctx context.Context) ([]domain.CatTrackRecord, error)
+	GetCatStats(ctx context.Context, catID int64) (*domain.CatStats, error)
+
+	CreateMission(ctx context.Context, mission domain.Mission) (int64, error)
+	GetMission(ctx context.Context, id int64) (*domain.Mission, error)
+	GetAllMissions(ctx context.Context, filter domain.MissionFilter) ([]domain.Mission, error)
+	FlagOverdueMissions(ctx context.Context, asOf time.Time) ([]domain.OverdueMission, error)
+	GetMissionsForCat(ctx context.Context, catID int64, limit, offset int) ([]domain.Mission, int, error)
+	MissionExists(ctx context.Context, id int64) (bool, error)
+	UpdateMissionCompleteStatus(ctx context.Context, id int64, complete bool) error
+	AssignCatToMission(ctx context.Context, missionID, catID int64) error
+	UpdateMissionLevels(ctx context.Context, id int64, priority domain.Priority, classification domain.Classification) error
+	DeleteMission(ctx context.Context, missionIDs []int64) error
+	DeleteUnassignedMission(ctx context.Context, missionIDs []int64) error
+	AreAllTargetsComplete(ctx context.Context, missionID int64) (bool, error)
+	CountActiveMissionsForCat(ctx context.Context, catID int64) (int, error)
+
+	AddTarget(ctx context.Context, missionID int64, target domain.Target) (int64, error)
+	GetTarget(ctx context.Context, targetID int64) (*domain.Target, error)
+	TargetExists(ctx context.Context, targetID int64) (bool, error)
+	GetTargetCountForMission(ctx context.Context, missionID int64) (int, error)
+	UpdateTarget(ctx context.Context, id int64, target domain.Target) error
+	UpdateNotes(ctx context.Context, targetID int64, notes string) error
+	UpdateCompleteStatus(ctx context.Context, targetID int64, complete bool) error
+	DeleteTarget(ctx context.Context, targetID int64) error
+	GetTargetsInArea(ctx context.Context, area domain.TargetArea) ([]domain.NearbyTarget, error)
+	GetTargets(ctx context.Context, filter domain.TargetFilter, limit, offset int) ([]domain.Target, int, error)
+	GetMissionClassifications(ctx context.Context, missionIDs []int64) (map[int64]domain.Classification, error)
+	MoveTarget(ctx context.Context, targetID, missionID int64) error
+
+	AddNoteRevision(ctx context.Context, targetID int64, kind domain.NoteRevisionKind, text, author string) (int, error)
+	GetNoteRevisions(ctx context.Context, targetID int64) ([]domain.NoteRevision, error)
+
+	AddAttachment(ctx context.Context, attachment domain.Attachment) (int64, error)
+	GetAttachment(ctx context.Context, id int64) (*domain.Attachment, error)
+	GetAttachments(ctx context.Context, targetID int64) ([]domain.Attachment, error)
+	DeleteAttachment(ctx context.Context, id int64) error
+	// AttachmentBlobInUse reports whether any attachment still refers to
+	// the blob with the given SHA-256.
+	AttachmentBlobInUse(ctx context.Context, sha256 string) (bool, error)
+
+	AddAuditEntry(ctx context.Context, entry domain.AuditEntry) (int64, error)
+	GetAuditEntries(ctx context.Context, filter domain.AuditFilter, limit, offset int) ([]domain.AuditEntry, int, error)
+
+	Search(ctx context.Context, query domain.SearchQuery, limit, offset int) ([]domain.SearchHit, int, error)
+
+	// EachCat and EachMission call fn with every cat or mission in ID
+	// order without loading them all at once. An error from fn stops the
+	// iteration and is returned.
+	EachCat(ctx context.Context, fn func(domain.SpyCat) error) error
+	EachMission(ctx context.Context, fn func(domain.Mission) error) error
+
+	CreateMissionTemplate(ctx context.Context, template domain.MissionTemplate) (int64, error)
+	GetMissionTemplate(ctx context.Context, id int64) (*domain.MissionTemplate, error)
+	GetMissionTemplates(ctx context.Context) ([]domain.MissionTemplate, error)
+	DeleteMissionTemplate(ctx context.Context, id int64) error
+}
+
+// BlobStore keeps the content of attachments. Blobs are addressed by the
+// hex encoded SHA-256 of their content, so a file uploaded twice is stored
+// once.
+type BlobStore interface {
+	// Put stores everything read from r and returns its key and size. An
+	// error from r is returned as is, wrapped, and nothing is stored.
+	Put(ctx context.Context, r io.Reader) (key string, size int64, err error)
+	// Open returns the content of a blob, or ErrBlobNotFound.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete removes a blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context) ([]BlobInfo, error)
+}
+
+// BlobInfo describes a stored blob.
+type BlobInfo struct {
+	Key      string
+	Size     int64
+	StoredAt time.Time
+}
@@ -0,0 +1,63 @@
+// Package textdiff computes line by line differences between texts.
+package textdiff
+
+import (
+	"strings"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// Lines returns the shortest edit turning a into b, line by line, based on
+// their longest common subsequence of lines. Deleted lines come before the
+// lines inserted in their place.
+func Lines(a, b string) []domain.DiffLine {
+	x, y := split(a), split(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]domain.DiffLine, 0, len(x)+len(y))
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			lines = append(lines, domain.DiffLine{Op: domain.DiffEqual, Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, domain.DiffLine{Op: domain.DiffDelete, Text: x[i]})
+			i++
+		default:
+			lines = append(lines, domain.DiffLine{Op: domain.DiffInsert, Text: y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		lines = append(lines, domain.DiffLine{Op: domain.DiffDelete, Text: x[i]})
+	}
+	for ; j < len(y); j++ {
+		lines = append(lines, domain.DiffLine{Op: domain.DiffInsert, Text: y[j]})
+	}
+	return lines
+}
+
+// split breaks s into lines. An empty text has no lines.
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
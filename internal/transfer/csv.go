@@ -0,0 +1,122 @@
+package transfer
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// Types of CSV rows. rowError is also the type of the NDJSON record and the
+// key of the JSON document ending an export that failed part way.
+const (
+	rowCat     = "cat"
+	rowMission = "mission"
+	rowTarget  = "target"
+	rowError   = "error"
+)
+
+// csvColumns lists every CSV column in header order.
+var csvColumns = []string{
+	"type", "id", "mission_id", "cat_id", "name", "years_of_experience", "breed", "salary",
+	"complete", "priority", "classification", "starts_at", "due_at", "redacted",
+	"country", "notes", "latitude", "longitude", "address",
+}
+
+// rowColumns lists the columns each type of row has values in.
+var rowColumns = map[string][]string{
+	rowCat:     {"type", "id", "name", "years_of_experience", "breed", "salary"},
+	rowMission: {"type", "id", "cat_id", "complete", "priority", "classification", "starts_at", "due_at", "redacted"},
+	rowTarget:  {"type", "id", "mission_id", "name", "country", "notes", "due_at", "latitude", "longitude", "address"},
+}
+
+// entityRows lists the types of row an entity is written as.
+var entityRows = map[Entity][]string{
+	Cats:     {rowCat},
+	Missions: {rowMission, rowTarget},
+}
+
+// csvHeader returns the columns used by the rows of entities, in header
+// order.
+func csvHeader(entities []Entity) []string {
+	var header []string
+	for _, column := range csvColumns {
+		for _, entity := range entities {
+			if usesColumn(entityRows[entity], column) {
+				header = append(header, column)
+				break
+			}
+		}
+	}
+	return header
+}
+
+func usesColumn(rows []string, column string) bool {
+	for _, row := range rows {
+		if slices.Contains(rowColumns[row], column) {
+			return true
+		}
+	}
+	return false
+}
+
+func catRow(c domain.SpyCat) map[string]string {
+	return map[string]string{
+		"type":                rowCat,
+		"id":                  formatInt(c.ID),
+		"name":                c.Name,
+		"years_of_experience": strconv.Itoa(c.YearsOfExperience),
+		"breed":               c.Breed,
+		"salary":              strconv.FormatFloat(c.Salary, 'f', -1, 64),
+	}
+}
+
+func missionRow(m domain.Mission) map[string]string {
+	row := map[string]string{
+		"type":           rowMission,
+		"id":             formatInt(m.ID),
+		"complete":       strconv.FormatBool(m.Complete),
+		"priority":       string(m.Priority),
+		"classification": string(m.Classification),
+		"starts_at":      formatTime(m.StartsAt),
+		"due_at":         formatTime(m.DueAt),
+		"redacted":       strconv.FormatBool(m.Redacted),
+	}
+	if m.CatID != nil {
+		row["cat_id"] = formatInt(*m.CatID)
+	}
+	return row
+}
+
+func targetRow(t domain.Target, missionID int64) map[string]string {
+	row := map[string]string{
+		"type":       rowTarget,
+		"id":         formatInt(t.ID),
+		"mission_id": formatInt(missionID),
+		"name":       t.Name,
+		"country":    t.Country,
+		"notes":      t.Notes,
+		"due_at":     formatTime(t.DueAt),
+		"address":    t.Address,
+	}
+	if t.Latitude != nil && t.Longitude != nil {
+		row["latitude"] = strconv.FormatFloat(*t.Latitude, 'f', -1, 64)
+		row["longitude"] = strconv.FormatFloat(*t.Longitude, 'f', -1, 64)
+	}
+	return row
+}
+
+func formatInt(v int64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
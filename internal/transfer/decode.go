@@ -0,0 +1,368 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// Decode reads the cats and missions of an import in format, in the order
+// they appear. Input that is not well formed, including an export that
+// ended with an error record, is reported as a domain.ValidationError
+// naming the line or record at fault. Whether the records make valid cats
+// and missions is left to the caller.
+func Decode(r io.Reader, format Format) ([]domain.ImportRecord, error) {
+	var records []domain.ImportRecord
+	var err error
+	switch format {
+	case CSV:
+		records, err = decodeCSV(r)
+	case NDJSON:
+		records, err = decodeNDJSON(r)
+	default:
+		records, err = decodeJSON(r)
+	}
+	if err == nil {
+		err = index(records)
+	}
+	if err != nil {
+		return nil, &domain.ValidationError{Err: err}
+	}
+	return records, nil
+}
+
+// index numbers the records of each kind and rejects IDs that are used by
+// more than one cat or more than one mission, as they could not tell which
+// is meant.
+func index(records []domain.ImportRecord) error {
+	var cats, missions int
+	catIDs, missionIDs := map[int64]bool{}, map[int64]bool{}
+	for i := range records {
+		r := &records[i]
+		seen, id := catIDs, int64(0)
+		if r.Cat != nil {
+			r.Index, id = cats, r.Cat.ID
+			cats++
+		} else {
+			r.Index, seen, id = missions, missionIDs, r.Mission.ID
+			missions++
+		}
+
+		if id == 0 {
+			continue
+		}
+		if seen[id] {
+			return fmt.Errorf("%s: id %d is used twice", position(*r), id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+func decodeJSON(r io.Reader) ([]domain.ImportRecord, error) {
+	var doc struct {
+		Cats     []domain.SpyCat  `json:"cats"`
+		Missions []domain.Mission `json:"missions"`
+		Error    *string          `json:"error"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if doc.Error != nil {
+		return nil, incomplete(*doc.Error)
+	}
+
+	records := make([]domain.ImportRecord, 0, len(doc.Cats)+len(doc.Missions))
+	for i := range doc.Cats {
+		records = append(records, domain.ImportRecord{Cat: &doc.Cats[i]})
+	}
+	for i := range doc.Missions {
+		records = append(records, domain.ImportRecord{Mission: &doc.Missions[i]})
+	}
+	return records, nil
+}
+
+func decodeNDJSON(r io.Reader) ([]domain.ImportRecord, error) {
+	br := bufio.NewReader(r)
+
+	var records []domain.ImportRecord
+	for line := 1; ; line++ {
+		b, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(b)) > 0 {
+			record, err := decodeLine(b)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			record.Line = line
+			records = append(records, record)
+		}
+
+		if err == io.EOF {
+			return records, nil
+		}
+	}
+}
+
+func decodeLine(b []byte) (domain.ImportRecord, error) {
+	var head struct {
+		Type  string `json:"type"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(b, &head); err != nil {
+		return domain.ImportRecord{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	switch head.Type {
+	case rowError:
+		return domain.ImportRecord{}, incomplete(head.Error)
+	case rowCat:
+		var cat domain.SpyCat
+		if err := json.Unmarshal(b, &cat); err != nil {
+			return domain.ImportRecord{}, fmt.Errorf("invalid cat: %w", err)
+		}
+		return domain.ImportRecord{Cat: &cat}, nil
+	case rowMission:
+		var mission domain.Mission
+		if err := json.Unmarshal(b, &mission); err != nil {
+			return domain.ImportRecord{}, fmt.Errorf("invalid mission: %w", err)
+		}
+		return domain.ImportRecord{Mission: &mission}, nil
+	default:
+		return domain.ImportRecord{}, fmt.Errorf("type must be %s or %s", rowCat, rowMission)
+	}
+}
+
+func decodeCSV(r io.Reader) ([]domain.ImportRecord, error) {
+	cr := csv.NewReader(r)
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("the header row is missing")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheets often start a CSV file with a byte order mark.
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if !slices.Contains(csvColumns, name) {
+			return nil, fmt.Errorf("line 1: unknown column %q", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("line 1: column %q appears twice", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["type"]; !ok {
+		return nil, errors.New("line 1: the type column is missing")
+	}
+
+	var records []domain.ImportRecord
+	// missions holds the missions read so far by their ID, for the target
+	// rows that follow them.
+	missions := make(map[int64]*domain.Mission)
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		row := make(map[string]string, len(columns))
+		for name, i := range columns {
+			row[name] = strings.TrimSpace(fields[i])
+		}
+
+		record, err := decodeRow(row, missions)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if record != nil {
+			record.Line = line
+			records = append(records, *record)
+		}
+	}
+}
+
+// decodeRow reads a CSV row. Target rows are added to their mission and
+// yield no record of their own.
+func decodeRow(row map[string]string, missions map[int64]*domain.Mission) (*domain.ImportRecord, error) {
+	typ := strings.ToLower(row["type"])
+	if typ == rowError {
+		return nil, incomplete(row["name"])
+	}
+	allowed, ok := rowColumns[typ]
+	if !ok {
+		return nil, fmt.Errorf("type must be %s, %s or %s", rowCat, rowMission, rowTarget)
+	}
+	for _, name := range csvColumns {
+		if row[name] != "" && !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("column %s does not apply to %s rows", name, typ)
+		}
+	}
+
+	p := rowParser{row: row}
+	switch typ {
+	case rowCat:
+		cat := domain.SpyCat{
+			ID:                p.id("id"),
+			Name:              row["name"],
+			YearsOfExperience: int(p.int("years_of_experience")),
+			Breed:             row["breed"],
+			Salary:            p.float("salary"),
+		}
+		return &domain.ImportRecord{Cat: &cat}, p.err
+
+	case rowMission:
+		mission := domain.Mission{
+			ID:             p.id("id"),
+			CatID:          p.idPtr("cat_id"),
+			Complete:       p.bool("complete"),
+			Priority:       domain.Priority(row["priority"]),
+			Classification: domain.Classification(row["classification"]),
+			StartsAt:       p.time("starts_at"),
+			DueAt:          p.time("due_at"),
+			Redacted:       p.bool("redacted"),
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+		if mission.ID != 0 {
+			missions[mission.ID] = &mission
+		}
+		return &domain.ImportRecord{Mission: &mission}, nil
+
+	default:
+		missionID := p.id("mission_id")
+		target := domain.Target{
+			ID:        p.id("id"),
+			Name:      row["name"],
+			Country:   row["country"],
+			Notes:     row["notes"],
+			DueAt:     p.time("due_at"),
+			Latitude:  p.floatPtr("latitude"),
+			Longitude: p.floatPtr("longitude"),
+			Address:   row["address"],
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+		mission, ok := missions[missionID]
+		if !ok {
+			return nil, errors.New("mission_id must be the id of a mission row above")
+		}
+		mission.Targets = append(mission.Targets, target)
+		return nil, nil
+	}
+}
+
+// incomplete reports an export that ended with an error record instead of
+// all of its cats and missions.
+func incomplete(message string) error {
+	return fmt.Errorf("the export is incomplete, it failed part way: %s", message)
+}
+
+// rowParser converts the values of a CSV row, remembering the first value
+// that could not be converted. Empty values convert to zero or nil.
+type rowParser struct {
+	row map[string]string
+	err error
+}
+
+func (p *rowParser) fail(column, want string) {
+	if p.err == nil {
+		p.err = fmt.Errorf("%s must be %s", column, want)
+	}
+}
+
+func (p *rowParser) int(column string) int64 {
+	v := p.row[column]
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		p.fail(column, "an integer")
+	}
+	return n
+}
+
+func (p *rowParser) id(column string) int64 {
+	id := p.int(column)
+	if id < 0 {
+		p.fail(column, "a positive integer")
+	}
+	return id
+}
+
+func (p *rowParser) idPtr(column string) *int64 {
+	if p.row[column] == "" {
+		return nil
+	}
+	id := p.id(column)
+	return &id
+}
+
+func (p *rowParser) float(column string) float64 {
+	v := p.row[column]
+	if v == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		p.fail(column, "a number")
+	}
+	return f
+}
+
+func (p *rowParser) floatPtr(column string) *float64 {
+	if p.row[column] == "" {
+		return nil
+	}
+	f := p.float(column)
+	return &f
+}
+
+func (p *rowParser) bool(column string) bool {
+	v := p.row[column]
+	if v == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		p.fail(column, "true or false")
+	}
+	return b
+}
+
+func (p *rowParser) time(column string) *time.Time {
+	v := p.row[column]
+	if v == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		p.fail(column, "an RFC 3339 timestamp")
+		return nil
+	}
+	return &t
+}
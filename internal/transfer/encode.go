@@ -0,0 +1,200 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// Encoder writes an export. Each entity given to NewEncoder is started once,
+// in the same order, before its cats or missions are written, and Close
+// finishes the document. Nothing is buffered beyond the current record.
+//
+// An export that cannot be completed is finished with Fail instead of
+// Close, which ends the document with an error record carrying message: an
+// "error" key in JSON, and a record or row of type "error" in NDJSON and
+// CSV. Decode rejects documents ending that way, so a partial export is
+// never mistaken for a complete one.
+type Encoder interface {
+	Start(entity Entity) error
+	WriteCat(cat domain.SpyCat) error
+	WriteMission(mission domain.Mission) error
+	Close() error
+	Fail(message string) error
+}
+
+// NewEncoder returns an encoder writing entities to w in format.
+func NewEncoder(w io.Writer, format Format, entities []Entity) Encoder {
+	switch format {
+	case CSV:
+		return &csvEncoder{w: csv.NewWriter(w), header: csvHeader(entities)}
+	case NDJSON:
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}
+	default:
+		return &jsonEncoder{w: w}
+	}
+}
+
+// jsonEncoder writes {"cats": [...], "missions": [...]} one element at a
+// time.
+type jsonEncoder struct {
+	w       io.Writer
+	started bool
+	empty   bool
+}
+
+func (e *jsonEncoder) Start(entity Entity) error {
+	open := "{"
+	if e.started {
+		open = "],"
+	}
+	e.started, e.empty = true, true
+	_, err := fmt.Fprintf(e.w, "%s%q:[", open, entity)
+	return err
+}
+
+func (e *jsonEncoder) WriteCat(cat domain.SpyCat) error {
+	return e.write(cat)
+}
+
+func (e *jsonEncoder) WriteMission(mission domain.Mission) error {
+	return e.write(mission)
+}
+
+func (e *jsonEncoder) write(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if !e.empty {
+		b = append([]byte{','}, b...)
+	}
+	e.empty = false
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	end := "]}\n"
+	if !e.started {
+		end = "{}\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+func (e *jsonEncoder) Fail(message string) error {
+	open := "{"
+	if e.started {
+		open = "],"
+	}
+	_, err := fmt.Fprintf(e.w, "%s%q:%q}\n", open, rowError, message)
+	return err
+}
+
+// ndjsonCat, ndjsonMission and ndjsonError are the lines of an NDJSON
+// document.
+type ndjsonCat struct {
+	Type string `json:"type"`
+	*domain.SpyCat
+}
+
+type ndjsonMission struct {
+	Type string `json:"type"`
+	*domain.Mission
+}
+
+type ndjsonError struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Start(Entity) error {
+	return nil
+}
+
+func (e *ndjsonEncoder) WriteCat(cat domain.SpyCat) error {
+	return e.enc.Encode(ndjsonCat{Type: rowCat, SpyCat: &cat})
+}
+
+func (e *ndjsonEncoder) WriteMission(mission domain.Mission) error {
+	return e.enc.Encode(ndjsonMission{Type: rowMission, Mission: &mission})
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+func (e *ndjsonEncoder) Fail(message string) error {
+	return e.enc.Encode(ndjsonError{Type: rowError, Error: message})
+}
+
+type csvEncoder struct {
+	w       *csv.Writer
+	header  []string
+	started bool
+}
+
+func (e *csvEncoder) Start(Entity) error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	return e.w.Write(e.header)
+}
+
+func (e *csvEncoder) WriteCat(cat domain.SpyCat) error {
+	return e.writeRow(catRow(cat))
+}
+
+func (e *csvEncoder) WriteMission(mission domain.Mission) error {
+	if err := e.writeRow(missionRow(mission)); err != nil {
+		return err
+	}
+	for _, t := range mission.Targets {
+		if err := e.writeRow(targetRow(t, mission.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *csvEncoder) writeRow(row map[string]string) error {
+	record := make([]string, len(e.header))
+	for i, column := range e.header {
+		record[i] = row[column]
+	}
+	if err := e.w.Write(record); err != nil {
+		return err
+	}
+	// Flushing every row surfaces write errors, such as from a client
+	// that went away, before the next row is read.
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) Close() error {
+	if !e.started {
+		if err := e.w.Write(e.header); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Fail writes an error row with message in the name column, which every
+// header has.
+func (e *csvEncoder) Fail(message string) error {
+	if err := e.Start(""); err != nil {
+		return err
+	}
+	return e.writeRow(map[string]string{"type": rowError, "name": message})
+}
@@ -0,0 +1,81 @@
+// Package transfer reads and writes cats and missions in the formats used
+// to export and import them in bulk: CSV, JSON and NDJSON.
+//
+// JSON is a single object with a "cats" and a "missions" array. NDJSON has
+// one cat or mission per line, each with a "type" of "cat" or "mission".
+// Both carry cats and missions as the API returns them. CSV is meant for
+// spreadsheets: every row has a type of "cat", "mission" or "target", the
+// columns of all three share one header, and target rows belong to the
+// mission row above them with the ID in their mission_id column.
+package transfer
+
+import (
+	"fmt"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+type Format string
+
+const (
+	CSV    Format = "csv"
+	JSON   Format = "json"
+	NDJSON Format = "ndjson"
+)
+
+// ParseFormat returns the format named by s, JSON when s is empty.
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(s); format {
+	case "":
+		return JSON, nil
+	case CSV, JSON, NDJSON:
+		return format, nil
+	default:
+		return "", &domain.ValidationError{Err: fmt.Errorf("format must be %s, %s or %s", CSV, JSON, NDJSON)}
+	}
+}
+
+// ContentType is the media type of documents in the format.
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case NDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
+
+// Entity is a kind of record that can be exported.
+type Entity string
+
+const (
+	Cats     Entity = "cats"
+	Missions Entity = "missions"
+)
+
+// Entities lists every entity in the order they are exported. Cats come
+// first, so an import of an export has every cat before its missions.
+var Entities = []Entity{Cats, Missions}
+
+// ParseEntity returns the entity named by s.
+func ParseEntity(s string) (Entity, error) {
+	switch entity := Entity(s); entity {
+	case Cats, Missions:
+		return entity, nil
+	default:
+		return "", fmt.Errorf("entities must be a comma separated list of %s and %s", Cats, Missions)
+	}
+}
+
+// position describes where a record was found, for error messages.
+func position(r domain.ImportRecord) string {
+	if r.Line > 0 {
+		return fmt.Sprintf("line %d", r.Line)
+	}
+	if r.Cat != nil {
+		return fmt.Sprintf("%s[%d]", Cats, r.Index)
+	}
+	return fmt.Sprintf("%s[%d]", Missions, r.Index)
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// ParsePagination reads the limit and offset query parameters. A missing
+// limit defaults to DefaultPageLimit; larger limits than MaxPageLimit are
+// rejected.
+func ParsePagination(r *http.Request) (limit, offset int, err error) {
+	limit = DefaultPageLimit
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > MaxPageLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", MaxPageLimit)
+		}
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+
+	return limit, offset, nil
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ParseBoolParam reads a boolean from the query parameter key. A missing
+// parameter yields nil.
+func ParseBoolParam(r *http.Request, key string) (*bool, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be true or false", key)
+	}
+	return &b, nil
+}
+
+// ParseFloatParam reads a required finite number from the query parameter
+// key.
+func ParseFloatParam(r *http.Request, key string) (float64, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return 0, fmt.Errorf("%s is required", key)
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%s must be a number", key)
+	}
+	return f, nil
+}
+
+// ParseIDParam reads a positive ID from the query parameter key. A missing
+// parameter yields nil.
+func ParseIDParam(r *http.Request, key string) (*int64, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id < 1 {
+		return nil, fmt.Errorf("%s must be a positive integer", key)
+	}
+	return &id, nil
+}
+
+// ParseListParam reads a comma separated list from the query parameter key,
+// converting each element with parse. A missing parameter yields nil.
+func ParseListParam[T any](r *http.Request, key string, parse func(string) (T, error)) ([]T, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return nil, nil
+	}
+
+	var values []T
+	for _, s := range strings.Split(v, ",") {
+		value, err := parse(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
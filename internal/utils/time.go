@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/illiakornyk/spy-cat/internal/domain"
+)
+
+// ParseTimeParam reads an RFC 3339 timestamp or a YYYY-MM-DD date (midnight
+// UTC) from the query parameter key. A missing parameter yields nil.
+func ParseTimeParam(r *http.Request, key string) (*time.Time, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", key)
+}
+
+// ParseTimeRange reads the <name>_after and <name>_before query parameters.
+func ParseTimeRange(r *http.Request, name string) (domain.TimeRange, error) {
+	after, err := ParseTimeParam(r, name+"_after")
+	if err != nil {
+		return domain.TimeRange{}, err
+	}
+	before, err := ParseTimeParam(r, name+"_before")
+	if err != nil {
+		return domain.TimeRange{}, err
+	}
+	return domain.TimeRange{After: after, Before: before}, nil
+}
@@ -0,0 +1,60 @@
+DROP INDEX IF EXISTS idx_targets_mission_id;
+DROP INDEX IF EXISTS idx_missions_cat_id;
+
+CREATE TABLE spy_cats_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    years_of_experience INTEGER,
+    breed TEXT,
+    salary REAL
+);
+
+INSERT INTO spy_cats_old (id, name, years_of_experience, breed, salary)
+SELECT id, name, years_of_experience, breed, salary
+FROM spy_cats;
+
+DROP TABLE spy_cats;
+ALTER TABLE spy_cats_old RENAME TO spy_cats;
+
+CREATE TABLE missions_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    cat_id INTEGER NULL,
+    complete BOOLEAN NOT NULL,
+    FOREIGN KEY (cat_id) REFERENCES spy_cats(id)
+);
+
+INSERT INTO missions_old (id, cat_id, complete)
+SELECT id, cat_id, complete
+FROM missions;
+
+DROP TABLE missions;
+ALTER TABLE missions_old RENAME TO missions;
+
+CREATE TABLE targets_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    mission_id INTEGER,
+    name TEXT,
+    country TEXT,
+    notes TEXT,
+    complete BOOLEAN,
+    FOREIGN KEY (mission_id) REFERENCES missions(id)
+);
+
+INSERT INTO targets_old (id, mission_id, name, country, notes, complete)
+SELECT id, mission_id, name, country, notes, complete
+FROM targets;
+
+DROP TABLE targets;
+ALTER TABLE targets_old RENAME TO targets;
+
+-- Restore the rows the up migration quarantined.
+INSERT INTO spy_cats (id, name, years_of_experience, breed, salary)
+SELECT id, name, years_of_experience, breed, salary
+FROM quarantined_spy_cats;
+
+INSERT INTO targets (id, mission_id, name, country, notes, complete)
+SELECT id, mission_id, name, country, notes, complete
+FROM quarantined_targets;
+
+DROP TABLE quarantined_spy_cats;
+DROP TABLE quarantined_targets;
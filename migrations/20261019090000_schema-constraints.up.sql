@@ -0,0 +1,122 @@
+-- Rebuild the tables with correct foreign keys, NOT NULL/CHECK constraints
+-- and indexes. SQLite cannot alter constraints in place, so each table is
+-- recreated and its rows copied over. Migrations run with foreign key
+-- enforcement disabled; use the integrity-check command to report rows
+-- that were already orphaned before this migration.
+--
+-- Rows the new constraints would reject cannot be copied. Rather than fail
+-- the migration, they are moved to quarantined_spy_cats and
+-- quarantined_targets along with the reason, where integrity-check reports
+-- them until they are fixed and copied back by hand, or deleted.
+
+-- Dropping a table also drops its AUTOINCREMENT sequence, so keep the old
+-- sequences to restore them once the tables are rebuilt.
+CREATE TEMP TABLE previous_sequence AS
+SELECT name, seq FROM sqlite_sequence
+WHERE name IN ('spy_cats', 'missions', 'targets');
+
+CREATE TABLE quarantined_spy_cats AS
+SELECT id, name, years_of_experience, breed, salary,
+    CASE
+        WHEN length(name) = 0 THEN 'name is empty'
+        WHEN years_of_experience < 0 THEN 'years_of_experience is negative'
+        WHEN salary IS NULL THEN 'salary is missing'
+        ELSE 'salary is not positive'
+    END AS reason
+FROM spy_cats
+WHERE length(name) = 0
+    OR years_of_experience < 0
+    OR salary IS NULL
+    OR salary <= 0;
+
+DELETE FROM spy_cats WHERE id IN (SELECT id FROM quarantined_spy_cats);
+
+CREATE TABLE quarantined_targets AS
+SELECT id, mission_id, name, country, notes, complete,
+    CASE
+        WHEN mission_id IS NULL THEN 'mission_id is missing'
+        WHEN name IS NULL OR length(name) = 0 THEN 'name is missing'
+        ELSE 'country is missing'
+    END AS reason
+FROM targets
+WHERE mission_id IS NULL
+    OR name IS NULL OR length(name) = 0
+    OR country IS NULL OR length(country) = 0;
+
+DELETE FROM targets WHERE id IN (SELECT id FROM quarantined_targets);
+
+CREATE TABLE spy_cats_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL CHECK (length(name) > 0),
+    years_of_experience INTEGER NOT NULL DEFAULT 0 CHECK (years_of_experience >= 0),
+    breed TEXT NOT NULL,
+    salary REAL NOT NULL CHECK (salary > 0)
+);
+
+INSERT INTO spy_cats_new (id, name, years_of_experience, breed, salary)
+SELECT id, name, COALESCE(years_of_experience, 0), COALESCE(breed, ''), salary
+FROM spy_cats;
+
+DROP TABLE spy_cats;
+ALTER TABLE spy_cats_new RENAME TO spy_cats;
+
+CREATE TABLE missions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    cat_id INTEGER NULL REFERENCES spy_cats(id) ON DELETE SET NULL,
+    complete BOOLEAN NOT NULL DEFAULT 0 CHECK (complete IN (0, 1))
+);
+
+INSERT INTO missions_new (id, cat_id, complete)
+SELECT id, cat_id, complete
+FROM missions;
+
+DROP TABLE missions;
+ALTER TABLE missions_new RENAME TO missions;
+
+CREATE INDEX idx_missions_cat_id ON missions(cat_id);
+
+CREATE TABLE targets_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    mission_id INTEGER NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (length(name) > 0),
+    country TEXT NOT NULL CHECK (length(country) > 0),
+    notes TEXT NOT NULL DEFAULT '',
+    complete BOOLEAN NOT NULL DEFAULT 0 CHECK (complete IN (0, 1))
+);
+
+INSERT INTO targets_new (id, mission_id, name, country, notes, complete)
+SELECT id, mission_id, name, country, COALESCE(notes, ''), COALESCE(complete, 0)
+FROM targets;
+
+DROP TABLE targets;
+ALTER TABLE targets_new RENAME TO targets;
+
+CREATE INDEX idx_targets_mission_id ON targets(mission_id);
+
+-- The rebuilt tables would continue from the highest id they were copied
+-- with, so new rows could take the id of a quarantined row and block it
+-- from being copied back. Continue from the highest id any of them ever
+-- used instead.
+DELETE FROM sqlite_sequence WHERE name IN ('spy_cats', 'missions', 'targets');
+
+INSERT INTO sqlite_sequence (name, seq)
+SELECT 'spy_cats', COALESCE(MAX(id), 0) FROM (
+    SELECT seq AS id FROM previous_sequence WHERE name = 'spy_cats'
+    UNION ALL SELECT id FROM spy_cats
+    UNION ALL SELECT id FROM quarantined_spy_cats
+);
+
+INSERT INTO sqlite_sequence (name, seq)
+SELECT 'missions', COALESCE(MAX(id), 0) FROM (
+    SELECT seq AS id FROM previous_sequence WHERE name = 'missions'
+    UNION ALL SELECT id FROM missions
+);
+
+INSERT INTO sqlite_sequence (name, seq)
+SELECT 'targets', COALESCE(MAX(id), 0) FROM (
+    SELECT seq AS id FROM previous_sequence WHERE name = 'targets'
+    UNION ALL SELECT id FROM targets
+    UNION ALL SELECT id FROM quarantined_targets
+);
+
+DROP TABLE previous_sequence;
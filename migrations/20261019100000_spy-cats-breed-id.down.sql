@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_spy_cats_breed_id;
+ALTER TABLE spy_cats DROP COLUMN breed_id;
@@ -0,0 +1,75 @@
+ALTER TABLE spy_cats ADD COLUMN breed_id TEXT;
+
+-- Backfill cats created before breed IDs were stored, using the breed list
+-- bundled with the application at the time of this migration.
+UPDATE spy_cats SET breed_id = CASE lower(breed)
+    WHEN 'abyssinian' THEN 'abys'
+    WHEN 'aegean' THEN 'aege'
+    WHEN 'american bobtail' THEN 'abob'
+    WHEN 'american curl' THEN 'acur'
+    WHEN 'american shorthair' THEN 'asho'
+    WHEN 'american wirehair' THEN 'awir'
+    WHEN 'arabian mau' THEN 'amau'
+    WHEN 'australian mist' THEN 'amis'
+    WHEN 'balinese' THEN 'bali'
+    WHEN 'bambino' THEN 'bamb'
+    WHEN 'bengal' THEN 'beng'
+    WHEN 'birman' THEN 'birm'
+    WHEN 'bombay' THEN 'bomb'
+    WHEN 'british longhair' THEN 'bslo'
+    WHEN 'british shorthair' THEN 'bsho'
+    WHEN 'burmese' THEN 'bure'
+    WHEN 'burmilla' THEN 'buri'
+    WHEN 'california spangled' THEN 'cspa'
+    WHEN 'chantilly-tiffany' THEN 'ctif'
+    WHEN 'chartreux' THEN 'char'
+    WHEN 'chausie' THEN 'chau'
+    WHEN 'cheetoh' THEN 'chee'
+    WHEN 'colorpoint shorthair' THEN 'csho'
+    WHEN 'cornish rex' THEN 'crex'
+    WHEN 'cymric' THEN 'cymr'
+    WHEN 'cyprus' THEN 'cypr'
+    WHEN 'devon rex' THEN 'drex'
+    WHEN 'donskoy' THEN 'dons'
+    WHEN 'dragon li' THEN 'lihu'
+    WHEN 'egyptian mau' THEN 'emau'
+    WHEN 'european burmese' THEN 'ebur'
+    WHEN 'exotic shorthair' THEN 'esho'
+    WHEN 'havana brown' THEN 'hbro'
+    WHEN 'himalayan' THEN 'hima'
+    WHEN 'japanese bobtail' THEN 'jbob'
+    WHEN 'javanese' THEN 'java'
+    WHEN 'khao manee' THEN 'khao'
+    WHEN 'korat' THEN 'kora'
+    WHEN 'kurilian' THEN 'kuri'
+    WHEN 'laperm' THEN 'lape'
+    WHEN 'maine coon' THEN 'mcoo'
+    WHEN 'malayan' THEN 'mala'
+    WHEN 'manx' THEN 'manx'
+    WHEN 'munchkin' THEN 'munc'
+    WHEN 'nebelung' THEN 'nebe'
+    WHEN 'norwegian forest cat' THEN 'norw'
+    WHEN 'ocicat' THEN 'ocic'
+    WHEN 'oriental' THEN 'orie'
+    WHEN 'persian' THEN 'pers'
+    WHEN 'pixie-bob' THEN 'pixi'
+    WHEN 'ragamuffin' THEN 'raga'
+    WHEN 'ragdoll' THEN 'ragd'
+    WHEN 'russian blue' THEN 'rblu'
+    WHEN 'savannah' THEN 'sava'
+    WHEN 'scottish fold' THEN 'sfol'
+    WHEN 'selkirk rex' THEN 'srex'
+    WHEN 'siamese' THEN 'siam'
+    WHEN 'siberian' THEN 'sibe'
+    WHEN 'singapura' THEN 'sing'
+    WHEN 'snowshoe' THEN 'snow'
+    WHEN 'somali' THEN 'soma'
+    WHEN 'sphynx' THEN 'sphy'
+    WHEN 'tonkinese' THEN 'tonk'
+    WHEN 'toyger' THEN 'toyg'
+    WHEN 'turkish angora' THEN 'tang'
+    WHEN 'turkish van' THEN 'tvan'
+    WHEN 'york chocolate' THEN 'ycho'
+END;
+
+CREATE INDEX IF NOT EXISTS idx_spy_cats_breed_id ON spy_cats(breed_id);
@@ -0,0 +1,4 @@
+DROP TABLE mission_assignments;
+ALTER TABLE targets DROP COLUMN completed_at;
+ALTER TABLE missions DROP COLUMN completed_at;
+ALTER TABLE missions DROP COLUMN assigned_at;
@@ -0,0 +1,23 @@
+-- When a cat was assigned to a mission and when missions and targets were
+-- completed. Rows that predate this migration keep NULL, as the times are
+-- not known.
+ALTER TABLE missions ADD COLUMN assigned_at DATETIME;
+ALTER TABLE missions ADD COLUMN completed_at DATETIME;
+ALTER TABLE targets ADD COLUMN completed_at DATETIME;
+
+-- Every cat a mission has been assigned to, so a cat keeps the missions it
+-- worked on in its history after they are reassigned to another cat. The
+-- current assignment is the one without unassigned_at.
+CREATE TABLE mission_assignments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    mission_id INTEGER NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    cat_id INTEGER NOT NULL REFERENCES spy_cats(id) ON DELETE CASCADE,
+    assigned_at DATETIME,
+    unassigned_at DATETIME
+);
+
+CREATE INDEX idx_mission_assignments_mission_id ON mission_assignments(mission_id);
+CREATE INDEX idx_mission_assignments_cat_id ON mission_assignments(cat_id);
+
+INSERT INTO mission_assignments (mission_id, cat_id)
+SELECT id, cat_id FROM missions WHERE cat_id IS NOT NULL ORDER BY id;
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_missions_completed_at;
+DROP INDEX IF EXISTS idx_missions_created_at;
+DROP INDEX IF EXISTS idx_spy_cats_created_at;
+
+ALTER TABLE targets DROP COLUMN updated_at;
+ALTER TABLE targets DROP COLUMN created_at;
+ALTER TABLE missions DROP COLUMN updated_at;
+ALTER TABLE missions DROP COLUMN created_at;
+ALTER TABLE spy_cats DROP COLUMN updated_at;
+ALTER TABLE spy_cats DROP COLUMN created_at;
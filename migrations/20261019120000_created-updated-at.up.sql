@@ -0,0 +1,26 @@
+-- Creation and last update times for every entity. SQLite cannot add a
+-- column with a non-constant default, so the columns are added empty and
+-- backfilled: from the earliest known mission or target time where there is
+-- one, from the time of this migration otherwise.
+ALTER TABLE spy_cats ADD COLUMN created_at DATETIME;
+ALTER TABLE spy_cats ADD COLUMN updated_at DATETIME;
+ALTER TABLE missions ADD COLUMN created_at DATETIME;
+ALTER TABLE missions ADD COLUMN updated_at DATETIME;
+ALTER TABLE targets ADD COLUMN created_at DATETIME;
+ALTER TABLE targets ADD COLUMN updated_at DATETIME;
+
+UPDATE missions SET
+    created_at = COALESCE(assigned_at, completed_at, CURRENT_TIMESTAMP),
+    updated_at = COALESCE(completed_at, assigned_at, CURRENT_TIMESTAMP);
+
+UPDATE targets SET
+    created_at = COALESCE((SELECT m.created_at FROM missions m WHERE m.id = targets.mission_id), CURRENT_TIMESTAMP),
+    updated_at = COALESCE(completed_at, (SELECT m.created_at FROM missions m WHERE m.id = targets.mission_id), CURRENT_TIMESTAMP);
+
+UPDATE spy_cats SET
+    created_at = COALESCE((SELECT MIN(m.assigned_at) FROM missions m WHERE m.cat_id = spy_cats.id), CURRENT_TIMESTAMP),
+    updated_at = COALESCE((SELECT MIN(m.assigned_at) FROM missions m WHERE m.cat_id = spy_cats.id), CURRENT_TIMESTAMP);
+
+CREATE INDEX IF NOT EXISTS idx_spy_cats_created_at ON spy_cats(created_at);
+CREATE INDEX IF NOT EXISTS idx_missions_created_at ON missions(created_at);
+CREATE INDEX IF NOT EXISTS idx_missions_completed_at ON missions(completed_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_missions_due_at;
+
+ALTER TABLE targets DROP COLUMN due_at;
+ALTER TABLE missions DROP COLUMN overdue_at;
+ALTER TABLE missions DROP COLUMN due_at;
+ALTER TABLE missions DROP COLUMN starts_at;
@@ -0,0 +1,8 @@
+-- Optional mission windows and target deadlines. overdue_at records when
+-- the scheduler first found a mission past its due time.
+ALTER TABLE missions ADD COLUMN starts_at DATETIME;
+ALTER TABLE missions ADD COLUMN due_at DATETIME;
+ALTER TABLE missions ADD COLUMN overdue_at DATETIME;
+ALTER TABLE targets ADD COLUMN due_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_missions_due_at ON missions(due_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_missions_classification;
+DROP INDEX IF EXISTS idx_missions_priority;
+
+ALTER TABLE missions DROP COLUMN classification;
+ALTER TABLE missions DROP COLUMN priority;
@@ -0,0 +1,9 @@
+-- How urgent and how sensitive a mission is. Existing missions become
+-- normal priority and public, which keeps them readable by everyone.
+ALTER TABLE missions ADD COLUMN priority TEXT NOT NULL DEFAULT 'normal'
+    CHECK (priority IN ('low', 'normal', 'high', 'critical'));
+ALTER TABLE missions ADD COLUMN classification TEXT NOT NULL DEFAULT 'public'
+    CHECK (classification IN ('public', 'confidential', 'secret', 'top-secret'));
+
+CREATE INDEX IF NOT EXISTS idx_missions_priority ON missions(priority);
+CREATE INDEX IF NOT EXISTS idx_missions_classification ON missions(classification);
@@ -0,0 +1,4 @@
+-- Sealed notes cannot be decrypted in SQL and are lost.
+ALTER TABLE targets DROP COLUMN notes_ciphertext;
+ALTER TABLE targets DROP COLUMN notes_data_key;
+ALTER TABLE targets DROP COLUMN notes_key_id;
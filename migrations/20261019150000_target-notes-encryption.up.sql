@@ -0,0 +1,7 @@
+-- Sealed target notes. When notes_key_id is set, notes is empty and the
+-- text is in notes_ciphertext, encrypted with a data key that is itself
+-- encrypted with the key-encryption key notes_key_id (see package
+-- envelope). Existing notes stay in plain text until rotate-keys runs.
+ALTER TABLE targets ADD COLUMN notes_key_id TEXT;
+ALTER TABLE targets ADD COLUMN notes_data_key BLOB;
+ALTER TABLE targets ADD COLUMN notes_ciphertext BLOB;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS target_note_revisions;
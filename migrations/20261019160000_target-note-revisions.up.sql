@@ -0,0 +1,24 @@
+-- Every change to a target's notes, and every observation appended to them,
+-- numbered per target. A "notes" revision holds the full notes after the
+-- change; an "observation" holds only the observation. The text is stored
+-- like targets.notes: in notes, or sealed in the notes_* columns.
+CREATE TABLE target_note_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    target_id INTEGER NOT NULL REFERENCES targets(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL CHECK (revision > 0),
+    kind TEXT NOT NULL CHECK (kind IN ('notes', 'observation')),
+    author TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    notes_key_id TEXT,
+    notes_data_key BLOB,
+    notes_ciphertext BLOB,
+    UNIQUE (target_id, revision)
+);
+
+-- The current notes of existing targets become their first revision, with
+-- no known author.
+INSERT INTO target_note_revisions (target_id, revision, kind, author, created_at, notes, notes_key_id, notes_data_key, notes_ciphertext)
+SELECT id, 1, 'notes', '', updated_at, notes, notes_key_id, notes_data_key, notes_ciphertext
+FROM targets
+WHERE notes != '' OR notes_key_id IS NOT NULL;
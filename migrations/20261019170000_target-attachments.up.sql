@@ -0,0 +1,15 @@
+-- Files attached to targets. The content lives in blob storage, addressed
+-- by its SHA-256, and may be shared by several attachments.
+CREATE TABLE target_attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    target_id INTEGER NOT NULL REFERENCES targets(id) ON DELETE CASCADE,
+    filename TEXT NOT NULL,
+    media_type TEXT NOT NULL,
+    size INTEGER NOT NULL CHECK (size >= 0),
+    sha256 TEXT NOT NULL CHECK (length(sha256) = 64),
+    uploaded_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_target_attachments_target_id ON target_attachments(target_id);
+CREATE INDEX idx_target_attachments_sha256 ON target_attachments(sha256);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_targets_location;
+
+ALTER TABLE targets DROP COLUMN address;
+ALTER TABLE targets DROP COLUMN longitude;
+ALTER TABLE targets DROP COLUMN latitude;
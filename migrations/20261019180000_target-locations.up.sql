@@ -0,0 +1,10 @@
+-- Where a target is. Coordinates are WGS 84 degrees and are either both set
+-- or both NULL; the address is free text for people reading the mission.
+ALTER TABLE targets ADD COLUMN latitude REAL
+    CHECK (latitude BETWEEN -90 AND 90);
+ALTER TABLE targets ADD COLUMN longitude REAL
+    CHECK (longitude BETWEEN -180 AND 180 AND (longitude IS NULL) = (latitude IS NULL));
+ALTER TABLE targets ADD COLUMN address TEXT NOT NULL DEFAULT '';
+
+-- Proximity searches narrow targets down to a bounding box first.
+CREATE INDEX IF NOT EXISTS idx_targets_location ON targets(latitude, longitude) WHERE latitude IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_audit_log_target_id;
+DROP INDEX IF EXISTS idx_audit_log_mission_id;
+
+DROP TABLE IF EXISTS audit_log;
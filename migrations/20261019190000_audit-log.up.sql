@@ -0,0 +1,16 @@
+-- Changes to missions and targets recorded for later review, such as a
+-- target moving from one mission to another. There are no foreign keys so
+-- entries outlive the missions and targets they mention. details holds the
+-- particulars of the action as a JSON object.
+CREATE TABLE audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action TEXT NOT NULL,
+    actor TEXT NOT NULL DEFAULT '',
+    mission_id INTEGER,
+    target_id INTEGER,
+    details TEXT NOT NULL DEFAULT '{}',
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_audit_log_mission_id ON audit_log(mission_id);
+CREATE INDEX idx_audit_log_target_id ON audit_log(target_id);
@@ -0,0 +1,19 @@
+DROP TRIGGER IF EXISTS missions_search_target_delete;
+DROP TRIGGER IF EXISTS missions_search_target_update;
+DROP TRIGGER IF EXISTS missions_search_target_insert;
+DROP TRIGGER IF EXISTS missions_search_cat_update;
+DROP TRIGGER IF EXISTS missions_search_delete;
+DROP TRIGGER IF EXISTS missions_search_update;
+DROP TRIGGER IF EXISTS missions_search_insert;
+
+DROP TABLE IF EXISTS missions_search;
+
+DROP TRIGGER IF EXISTS targets_search_delete;
+DROP TRIGGER IF EXISTS targets_search_update;
+DROP TRIGGER IF EXISTS targets_search_insert;
+DROP TRIGGER IF EXISTS cats_search_delete;
+DROP TRIGGER IF EXISTS cats_search_update;
+DROP TRIGGER IF EXISTS cats_search_insert;
+
+DROP TABLE IF EXISTS targets_search;
+DROP TABLE IF EXISTS cats_search;
@@ -0,0 +1,96 @@
+-- Full-text indexes for /api/v1/search, kept in sync by the triggers below.
+-- The rowid of each row is the id of the cat or target it indexes. Targets
+-- index the notes column only, so notes sealed at rest stay unsearchable
+-- rather than being copied into the index in plain text.
+CREATE VIRTUAL TABLE cats_search USING fts5(
+    name, breed,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE VIRTUAL TABLE targets_search USING fts5(
+    name, country, notes,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO cats_search (rowid, name, breed)
+SELECT id, name, COALESCE(breed, '') FROM spy_cats;
+
+INSERT INTO targets_search (rowid, name, country, notes)
+SELECT id, name, country, notes FROM targets;
+
+CREATE TRIGGER cats_search_insert AFTER INSERT ON spy_cats BEGIN
+    INSERT INTO cats_search (rowid, name, breed) VALUES (new.id, new.name, COALESCE(new.breed, ''));
+END;
+
+CREATE TRIGGER cats_search_update AFTER UPDATE OF name, breed ON spy_cats BEGIN
+    UPDATE cats_search SET name = new.name, breed = COALESCE(new.breed, '') WHERE rowid = new.id;
+END;
+
+CREATE TRIGGER cats_search_delete AFTER DELETE ON spy_cats BEGIN
+    DELETE FROM cats_search WHERE rowid = old.id;
+END;
+
+CREATE TRIGGER targets_search_insert AFTER INSERT ON targets BEGIN
+    INSERT INTO targets_search (rowid, name, country, notes) VALUES (new.id, new.name, new.country, new.notes);
+END;
+
+CREATE TRIGGER targets_search_update AFTER UPDATE OF name, country, notes ON targets BEGIN
+    UPDATE targets_search SET name = new.name, country = new.country, notes = new.notes WHERE rowid = new.id;
+END;
+
+CREATE TRIGGER targets_search_delete AFTER DELETE ON targets BEGIN
+    DELETE FROM targets_search WHERE rowid = old.id;
+END;
+
+-- Full-text index of missions for /api/v1/search, kept in sync by the
+-- triggers below. The rowid of each row is the id of the mission; cat holds
+-- the name of the assigned cat and targets the names and country codes of
+-- the mission's targets, none of which is withheld from any caller. Notes
+-- are not indexed here.
+CREATE VIRTUAL TABLE missions_search USING fts5(
+    cat, targets,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO missions_search (rowid, cat, targets)
+SELECT id,
+    COALESCE((SELECT name FROM spy_cats WHERE spy_cats.id = missions.cat_id), ''),
+    COALESCE((SELECT group_concat(name || ' ' || country, ' ') FROM targets WHERE targets.mission_id = missions.id), '')
+FROM missions;
+
+CREATE TRIGGER missions_search_insert AFTER INSERT ON missions BEGIN
+    INSERT INTO missions_search (rowid, cat, targets)
+    VALUES (new.id, COALESCE((SELECT name FROM spy_cats WHERE spy_cats.id = new.cat_id), ''), '');
+END;
+
+CREATE TRIGGER missions_search_update AFTER UPDATE OF cat_id ON missions BEGIN
+    UPDATE missions_search SET cat = COALESCE((SELECT name FROM spy_cats WHERE spy_cats.id = new.cat_id), '')
+    WHERE rowid = new.id;
+END;
+
+CREATE TRIGGER missions_search_delete AFTER DELETE ON missions BEGIN
+    DELETE FROM missions_search WHERE rowid = old.id;
+END;
+
+CREATE TRIGGER missions_search_cat_update AFTER UPDATE OF name ON spy_cats BEGIN
+    UPDATE missions_search SET cat = new.name
+    WHERE rowid IN (SELECT id FROM missions WHERE cat_id = new.id);
+END;
+
+CREATE TRIGGER missions_search_target_insert AFTER INSERT ON targets BEGIN
+    UPDATE missions_search
+    SET targets = COALESCE((SELECT group_concat(name || ' ' || country, ' ') FROM targets WHERE mission_id = new.mission_id), '')
+    WHERE rowid = new.mission_id;
+END;
+
+CREATE TRIGGER missions_search_target_update AFTER UPDATE OF name, country, mission_id ON targets BEGIN
+    UPDATE missions_search
+    SET targets = COALESCE((SELECT group_concat(name || ' ' || country, ' ') FROM targets WHERE mission_id = missions_search.rowid), '')
+    WHERE rowid IN (old.mission_id, new.mission_id);
+END;
+
+CREATE TRIGGER missions_search_target_delete AFTER DELETE ON targets BEGIN
+    UPDATE missions_search
+    SET targets = COALESCE((SELECT group_concat(name || ' ' || country, ' ') FROM targets WHERE mission_id = old.mission_id), '')
+    WHERE rowid = old.mission_id;
+END;
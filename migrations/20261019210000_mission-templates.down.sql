@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_mission_template_targets_template_id;
+
+DROP TABLE IF EXISTS mission_template_targets;
+DROP TABLE IF EXISTS mission_templates;
@@ -0,0 +1,25 @@
+-- Named sets of targets that missions can be created from. Notes of
+-- template targets are placeholders copied into every new mission, so they
+-- are kept in plain text and should not hold intelligence.
+CREATE TABLE mission_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    priority TEXT NOT NULL DEFAULT 'normal'
+        CHECK (priority IN ('low', 'normal', 'high', 'critical')),
+    classification TEXT NOT NULL DEFAULT 'public'
+        CHECK (classification IN ('public', 'confidential', 'secret', 'top-secret')),
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- Targets of a template, in the order they are created in.
+CREATE TABLE mission_template_targets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    template_id INTEGER NOT NULL REFERENCES mission_templates(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    country TEXT NOT NULL,
+    notes TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_mission_template_targets_template_id ON mission_template_targets(template_id);
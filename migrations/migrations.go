@@ -0,0 +1,8 @@
+// Package migrations embeds the SQL schema migrations so the binary can
+// migrate its database without the migrations folder on disk.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS